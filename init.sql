@@ -1,13 +1,133 @@
 -- Remove PostgreSQL specific extension creation
 -- CREATE EXTENSION IF NOT EXISTS \"uuid-ossp\";
 
+-- Colleges the platform runs for; every user and company belongs to one
+CREATE TABLE institutions (
+    institution_id VARCHAR(36) PRIMARY KEY,
+    name VARCHAR(255) UNIQUE NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL
+);
+
+INSERT INTO institutions (institution_id, name) VALUES
+    ('00000000-0000-0000-0000-000000000001', 'Default institution');
+
+-- Roles without an institution, the built-in ones included, are shared by
+-- every institution; the others can only be used by their own institution.
+-- scope repeats institution_id, with '' for shared roles, so that every
+-- institution has its own role names.
+CREATE TABLE roles (
+    scope VARCHAR(36) DEFAULT '' NOT NULL,
+    name VARCHAR(64) NOT NULL,
+    institution_id VARCHAR(36) NULL,
+    description TEXT,
+    is_system BOOLEAN DEFAULT FALSE NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    PRIMARY KEY (scope, name),
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    CHECK (scope = COALESCE(institution_id, ''))
+);
+
+CREATE TABLE role_permissions (
+    scope VARCHAR(36) DEFAULT '' NOT NULL,
+    role VARCHAR(64) NOT NULL,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (scope, role, permission),
+    FOREIGN KEY (scope, role) REFERENCES roles(scope, name) ON DELETE CASCADE
+);
+
+-- Built-in roles, further roles are managed through /v1/roles
+INSERT INTO roles (name, description, is_system) VALUES
+    ('superadmin', 'Administrator of every institution', TRUE),
+    ('admin', 'Platform administrator', TRUE),
+    ('manager', 'Placement manager', TRUE),
+    ('user', 'Placement officer', TRUE),
+    ('student', 'Student being placed', TRUE);
+
+INSERT INTO role_permissions (role, permission) VALUES
+    ('superadmin', 'company:create'),
+    ('superadmin', 'company:read'),
+    ('superadmin', 'company:update'),
+    ('superadmin', 'company:approve'),
+    ('superadmin', 'user:create'),
+    ('superadmin', 'user:read'),
+    ('superadmin', 'user:invite'),
+    ('superadmin', 'user:update'),
+    ('superadmin', 'user:deactivate'),
+    ('superadmin', 'user:delete'),
+    ('superadmin', 'user:unlock'),
+    ('superadmin', 'user:signout'),
+    ('superadmin', 'role:read'),
+    ('superadmin', 'role:manage'),
+    ('superadmin', 'audit:read'),
+    ('superadmin', 'student:read'),
+    ('superadmin', 'student:verify'),
+    ('superadmin', 'drive:read'),
+    ('superadmin', 'drive:manage'),
+    ('superadmin', 'application:read'),
+    ('superadmin', 'round:manage'),
+    ('superadmin', 'institution:manage'),
+    ('admin', 'company:create'),
+    ('admin', 'company:read'),
+    ('admin', 'company:update'),
+    ('admin', 'company:approve'),
+    ('admin', 'user:create'),
+    ('admin', 'user:read'),
+    ('admin', 'user:invite'),
+    ('admin', 'user:update'),
+    ('admin', 'user:deactivate'),
+    ('admin', 'user:delete'),
+    ('admin', 'user:unlock'),
+    ('admin', 'user:signout'),
+    ('admin', 'role:read'),
+    ('admin', 'role:manage'),
+    ('admin', 'audit:read'),
+    ('admin', 'student:read'),
+    ('admin', 'student:verify'),
+    ('admin', 'drive:read'),
+    ('admin', 'drive:manage'),
+    ('admin', 'application:read'),
+    ('admin', 'round:manage'),
+    ('manager', 'company:create'),
+    ('manager', 'company:read'),
+    ('manager', 'company:update'),
+    ('manager', 'user:create'),
+    ('manager', 'user:read'),
+    ('manager', 'role:read'),
+    ('manager', 'student:read'),
+    ('manager', 'student:verify'),
+    ('manager', 'drive:read'),
+    ('manager', 'drive:manage'),
+    ('manager', 'application:read'),
+    ('manager', 'round:manage'),
+    ('user', 'company:read'),
+    ('user', 'company:update'),
+    ('user', 'student:read'),
+    ('user', 'student:verify'),
+    ('user', 'drive:read'),
+    ('user', 'drive:manage'),
+    ('user', 'application:read'),
+    ('user', 'round:manage'),
+    ('student', 'student:profile'),
+    ('student', 'drive:read'),
+    ('student', 'application:apply');
+
 CREATE TABLE users (
     user_id VARCHAR(36) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
     user_name VARCHAR(100) NOT NULL,
     email VARCHAR(255) UNIQUE NOT NULL,
     pass TEXT NOT NULL,
-    role TEXT NOT NULL CHECK (role IN ('admin', 'manager', 'user')),
-    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+    role VARCHAR(64) NOT NULL,
+    role_scope VARCHAR(36) DEFAULT '' NOT NULL,
+    status VARCHAR(16) DEFAULT 'active' NOT NULL CHECK (status IN ('active', 'deactivated')),
+    email_verified_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    FOREIGN KEY (role_scope, role) REFERENCES roles(scope, name),
+    INDEX idx_users_created (institution_id, created_at, user_id),
+    INDEX idx_users_name (user_name)
 );
 
 CREATE TABLE data (
@@ -22,29 +142,358 @@ CREATE TABLE account_data_map (
 
 CREATE TABLE company_data (
     id VARCHAR(255) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
     company_name VARCHAR(255) NOT NULL,
     company_address TEXT,
-    drive VARCHAR(255),
-    type_of_drive VARCHAR(100),
     follow_up TEXT,
     is_contacted BOOLEAN DEFAULT FALSE,
     remarks TEXT,
     contact_details TEXT,
     hr_details TEXT,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    owner_id VARCHAR(36),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    INDEX idx_company_data_institution (institution_id),
+    INDEX idx_company_data_owner (owner_id)
 );
 
 CREATE TABLE company_data_approval (
     id VARCHAR(255) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
     company_name VARCHAR(255) NOT NULL,
     company_address TEXT,
-    drive VARCHAR(255),
-    type_of_drive VARCHAR(100),
     follow_up TEXT,
     is_contacted BOOLEAN DEFAULT FALSE,
     remarks TEXT,
     contact_details TEXT,
     hr_details TEXT,
     is_approved BOOLEAN DEFAULT FALSE,
-    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    INDEX idx_company_data_approval_institution (institution_id)
+);
+
+CREATE TABLE jwt_keys (
+    kid VARCHAR(36) PRIMARY KEY,
+    secret VARBINARY(64) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('active', 'retired')),
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    retired_at DATETIME NULL
+);
+
+CREATE TABLE sessions (
+    session_id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    -- The institution tokens of the session act in, switchable by super-admins
+    institution_id VARCHAR(36) NOT NULL,
+    mfa BOOLEAN DEFAULT FALSE NOT NULL,
+    ip VARCHAR(45) DEFAULT '' NOT NULL,
+    user_agent VARCHAR(255) DEFAULT '' NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    last_seen_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    revoked_at DATETIME NULL,
+    INDEX idx_sessions_user (user_id, last_seen_at)
+);
+
+CREATE TABLE refresh_tokens (
+    token_id VARCHAR(36) PRIMARY KEY,
+    session_id VARCHAR(36) NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (session_id) REFERENCES sessions(session_id) ON DELETE CASCADE
+);
+
+CREATE TABLE revoked_tokens (
+    jti VARCHAR(36) PRIMARY KEY,
+    expires_at DATETIME NOT NULL
+);
+
+CREATE TABLE invites (
+    invite_id VARCHAR(36) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    role VARCHAR(64) NOT NULL,
+    role_scope VARCHAR(36) DEFAULT '' NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    invited_by VARCHAR(36) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    redeemed_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    FOREIGN KEY (role_scope, role) REFERENCES roles(scope, name) ON DELETE CASCADE
+);
+
+CREATE TABLE password_resets (
+    token_id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE email_verifications (
+    token_id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    email VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE login_attempts (
+    throttle_key VARCHAR(320) PRIMARY KEY,
+    failures INT NOT NULL,
+    first_failure_at DATETIME(3) NOT NULL,
+    last_failure_at DATETIME(3) NOT NULL,
+    locked_until DATETIME(3) NULL
+);
+
+CREATE TABLE mfa_totp (
+    user_id VARCHAR(36) PRIMARY KEY,
+    -- Changes with every enrollment, so keys can tell a re-enrolled factor apart
+    enrollment_id VARCHAR(36) NOT NULL,
+    secret VARCHAR(64) NOT NULL,
+    confirmed_at DATETIME NULL,
+    last_used_step BIGINT DEFAULT 0 NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE mfa_backup_codes (
+    user_id VARCHAR(36) NOT NULL,
+    code_hash CHAR(64) NOT NULL,
+    used_at DATETIME NULL,
+    PRIMARY KEY (user_id, code_hash),
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE mfa_challenges (
+    challenge_id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    token_hash CHAR(64) UNIQUE NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE oidc_logins (
+    state_hash CHAR(64) PRIMARY KEY,
+    nonce VARCHAR(64) NOT NULL,
+    code_verifier VARCHAR(128) NOT NULL,
+    device_id VARCHAR(255) NOT NULL,
+    expires_at DATETIME NOT NULL,
+    used_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL
+);
+
+CREATE TABLE api_keys (
+    key_id VARCHAR(36) PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    prefix VARCHAR(16) NOT NULL,
+    key_hash CHAR(64) UNIQUE NOT NULL,
+    -- The second factor enrollment the creating session had passed, if any
+    mfa_enrollment_id VARCHAR(36) NULL,
+    expires_at DATETIME NULL,
+    last_used_at DATETIME NULL,
+    revoked_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    INDEX idx_api_keys_user (user_id, created_at)
+);
+
+CREATE TABLE api_key_scopes (
+    key_id VARCHAR(36) NOT NULL,
+    permission VARCHAR(64) NOT NULL,
+    PRIMARY KEY (key_id, permission),
+    FOREIGN KEY (key_id) REFERENCES api_keys(key_id) ON DELETE CASCADE
+);
+
+CREATE TABLE password_history (
+    history_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    user_id VARCHAR(36) NOT NULL,
+    pass_hash TEXT NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    INDEX idx_password_history_user (user_id, history_id)
+);
+
+-- Security events are only ever appended, the triggers reject changes to past entries
+CREATE TABLE auth_events (
+    event_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    type VARCHAR(32) NOT NULL,
+    institution_id VARCHAR(36) NULL,
+    actor_id VARCHAR(36) NULL,
+    subject_id VARCHAR(36) NULL,
+    email VARCHAR(255) NULL,
+    ip VARCHAR(45) NULL,
+    detail TEXT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    INDEX idx_auth_events_institution (institution_id, event_id),
+    INDEX idx_auth_events_type (type, event_id),
+    INDEX idx_auth_events_actor (actor_id, event_id),
+    INDEX idx_auth_events_subject (subject_id, event_id),
+    INDEX idx_auth_events_created (created_at)
+);
+
+CREATE TRIGGER auth_events_no_update BEFORE UPDATE ON auth_events FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'auth_events is append-only';
+
+CREATE TRIGGER auth_events_no_delete BEFORE DELETE ON auth_events FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'auth_events is append-only';
+
+-- Every write to company data, chained by hash so that edits or deletions
+-- of past entries are detected by `main verify-audit`
+CREATE TABLE company_audit (
+    entry_id BIGINT AUTO_INCREMENT PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
+    company_id VARCHAR(255) NOT NULL,
+    actor_id VARCHAR(36) NOT NULL,
+    action VARCHAR(16) NOT NULL,
+    before_json MEDIUMTEXT NOT NULL,
+    after_json MEDIUMTEXT NOT NULL,
+    created_at DATETIME(6) NOT NULL,
+    prev_hash CHAR(64) NOT NULL,
+    hash CHAR(64) UNIQUE NOT NULL,
+    INDEX idx_company_audit_company (institution_id, company_id, entry_id)
+);
+
+-- The last entry of the chain, locked by writers to append one at a time
+CREATE TABLE company_audit_head (
+    id TINYINT PRIMARY KEY CHECK (id = 1),
+    entry_id BIGINT NOT NULL,
+    hash VARCHAR(64) NOT NULL
+);
+
+INSERT INTO company_audit_head (id, entry_id, hash) VALUES (1, 0, '');
+
+CREATE TRIGGER company_audit_no_update BEFORE UPDATE ON company_audit FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'company_audit is append-only';
+
+CREATE TRIGGER company_audit_no_delete BEFORE DELETE ON company_audit FOR EACH ROW
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'company_audit is append-only';
+
+-- Profiles of users with the student role, kept by the students themselves
+-- and verified by placement officers
+CREATE TABLE students (
+    user_id VARCHAR(36) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
+    roll_number VARCHAR(32) NOT NULL,
+    branch VARCHAR(100) NOT NULL,
+    batch SMALLINT NOT NULL,
+    cgpa DECIMAL(4, 2) NOT NULL CHECK (cgpa BETWEEN 0 AND 10),
+    backlogs SMALLINT NOT NULL DEFAULT 0 CHECK (backlogs >= 0),
+    phone VARCHAR(20),
+    personal_email VARCHAR(255),
+    verification_status VARCHAR(16) DEFAULT 'pending' NOT NULL CHECK (verification_status IN ('pending', 'verified', 'rejected')),
+    verification_note TEXT,
+    verified_by VARCHAR(36) NULL,
+    verified_at DATETIME NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    -- Bumped by every write, so writers can tell when the row changed since they read it
+    version INT DEFAULT 1 NOT NULL,
+    FOREIGN KEY (user_id) REFERENCES users(user_id) ON DELETE CASCADE,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    UNIQUE INDEX idx_students_roll_number (institution_id, roll_number),
+    INDEX idx_students_batch (institution_id, batch, branch)
+);
+
+CREATE TABLE student_skills (
+    user_id VARCHAR(36) NOT NULL,
+    skill VARCHAR(64) NOT NULL,
+    PRIMARY KEY (user_id, skill),
+    FOREIGN KEY (user_id) REFERENCES students(user_id) ON DELETE CASCADE
+);
+
+CREATE TABLE drives (
+    drive_id VARCHAR(36) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
+    company_id VARCHAR(255) NOT NULL,
+    ctc BIGINT NOT NULL DEFAULT 0,
+    stipend BIGINT NOT NULL DEFAULT 0,
+    location VARCHAR(255),
+    drive_type VARCHAR(16) NOT NULL CHECK (drive_type IN ('on_campus', 'off_campus', 'pool', 'virtual')),
+    registration_deadline DATETIME NOT NULL,
+    drive_date DATETIME NOT NULL,
+    min_cgpa DECIMAL(4, 2) NOT NULL DEFAULT 0 CHECK (min_cgpa BETWEEN 0 AND 10),
+    max_backlogs SMALLINT NULL CHECK (max_backlogs >= 0),
+    eligible_batch SMALLINT NOT NULL DEFAULT 0,
+    status VARCHAR(16) DEFAULT 'scheduled' NOT NULL CHECK (status IN ('scheduled', 'completed', 'cancelled')),
+    created_by VARCHAR(36) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    FOREIGN KEY (company_id) REFERENCES company_data(id),
+    INDEX idx_drives_date (institution_id, status, drive_date),
+    INDEX idx_drives_company (company_id)
+);
+
+CREATE TABLE drive_job_roles (
+    drive_id VARCHAR(36) NOT NULL,
+    position SMALLINT NOT NULL,
+    job_role VARCHAR(100) NOT NULL,
+    PRIMARY KEY (drive_id, position),
+    FOREIGN KEY (drive_id) REFERENCES drives(drive_id) ON DELETE CASCADE
+);
+
+-- Branches allowed to apply to a drive; a drive without any accepts every branch
+CREATE TABLE drive_eligible_branches (
+    drive_id VARCHAR(36) NOT NULL,
+    branch VARCHAR(100) NOT NULL,
+    PRIMARY KEY (drive_id, branch),
+    FOREIGN KEY (drive_id) REFERENCES drives(drive_id) ON DELETE CASCADE
+);
+
+-- Student applications to drives. Withdrawn applications are kept so the
+-- student can apply again while registration is open.
+CREATE TABLE applications (
+    application_id VARCHAR(36) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
+    drive_id VARCHAR(36) NOT NULL,
+    student_id VARCHAR(36) NOT NULL,
+    status VARCHAR(16) DEFAULT 'applied' NOT NULL CHECK (status IN ('applied', 'withdrawn')),
+    applied_at DATETIME NOT NULL,
+    withdrawn_at DATETIME NULL,
+    updated_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    FOREIGN KEY (drive_id) REFERENCES drives(drive_id),
+    FOREIGN KEY (student_id) REFERENCES students(user_id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_applications_student (drive_id, student_id),
+    INDEX idx_applications_by_student (student_id)
+);
+
+-- Selection rounds of a drive, in the order candidates go through them
+CREATE TABLE drive_rounds (
+    round_id VARCHAR(36) PRIMARY KEY,
+    institution_id VARCHAR(36) NOT NULL,
+    drive_id VARCHAR(36) NOT NULL,
+    position SMALLINT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    created_at DATETIME DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    FOREIGN KEY (institution_id) REFERENCES institutions(institution_id),
+    FOREIGN KEY (drive_id) REFERENCES drives(drive_id) ON DELETE CASCADE,
+    UNIQUE INDEX idx_drive_rounds_name (drive_id, name),
+    INDEX idx_drive_rounds_position (drive_id, position)
+);
+
+CREATE TABLE round_results (
+    round_id VARCHAR(36) NOT NULL,
+    application_id VARCHAR(36) NOT NULL,
+    status VARCHAR(16) NOT NULL CHECK (status IN ('shortlisted', 'rejected', 'absent')),
+    score DECIMAL(6, 2) NULL CHECK (score >= 0),
+    recorded_by VARCHAR(36) NOT NULL,
+    recorded_at DATETIME NOT NULL,
+    PRIMARY KEY (round_id, application_id),
+    FOREIGN KEY (round_id) REFERENCES drive_rounds(round_id) ON DELETE CASCADE,
+    FOREIGN KEY (application_id) REFERENCES applications(application_id) ON DELETE CASCADE
 );
@@ -1,36 +1,49 @@
 package main
 
 import (
-	"crypto/rand"
+	"context"
 	"database/sql"
-	"encoding/base64"
+	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strconv"
+	"strings"
 	"time"
 
 	_ "github.com/go-sql-driver/mysql"
 	// _ "github.com/lib/pq"
 
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/pkg/ldap"
+	"backend/pkg/mailer"
+	"backend/pkg/oidc"
+	"backend/pkg/password"
+	"backend/pkg/throttle"
+	applicationHandler "backend/services/applicationd/handler"
+	applicationRepository "backend/services/applicationd/repository"
+	"backend/services/applicationd/usecase/application"
+	"backend/services/applicationd/usecase/round"
 	dataHandler "backend/services/datad/handler"
 	dataRepository "backend/services/datad/repository"
 	"backend/services/datad/usecase/data"
+	"backend/services/datad/usecase/drive"
+	studentHandler "backend/services/studentd/handler"
+	studentRepository "backend/services/studentd/repository"
+	"backend/services/studentd/usecase/student"
 	userHandler "backend/services/userd/handler"
 	"backend/services/userd/repository"
+	"backend/services/userd/usecase/institution"
+	"backend/services/userd/usecase/role"
 	"backend/services/userd/usecase/user"
 )
 
 const PORT = "8080"
 
-func generateSecret(length int) (string, error) {
-	bytes := make([]byte, length)
-	_, err := rand.Read(bytes)
-	if err != nil {
-		return "", err
-	}
-	return base64.URLEncoding.EncodeToString(bytes), nil
-}
+// Retired signing keys must outlive the tokens they signed (1 hour)
+const defaultKeyRetention = 2 * time.Hour
 
 func main() {
 	connStr := fmt.Sprintf("%s:%s@tcp(%s)/%s?parseTime=true",
@@ -54,21 +67,345 @@ func main() {
 	}
 	log.Println("Database connection successful")
 
-	jwtSecret, err := generateSecret(32)
+	keys, err := newKeyStore(db)
+	if err != nil {
+		log.Fatalf("Error configuring JWT key store: %v", err)
+	}
+	keyRetention := getEnvDuration("JWT_KEY_RETENTION", defaultKeyRetention)
+
+	if len(os.Args) > 1 && os.Args[1] == "rotate-keys" {
+		rotateKeys(keys, keyRetention, os.Args[2:])
+		return
+	}
+
+	hasher, err := newPasswordHasher()
+	if err != nil {
+		log.Fatalf("Error configuring password hasher: %v", err)
+	}
+	passwordPolicy, err := newPasswordPolicy()
+	if err != nil {
+		log.Fatalf("Error configuring password policy: %v", err)
+	}
+	revocations := auth.NewDBRevocationList(db)
+	// Kept out of the database so its writers cannot re-seal an edited chain
+	auditKey := getEnv("AUDIT_HMAC_KEY", "")
+	if auditKey == "" {
+		log.Fatalf("Error configuring audit trail: AUDIT_HMAC_KEY is not set")
+	}
+	dataRepo := dataRepository.NewDataRepository(db, []byte(auditKey))
+	userRepo := repository.NewUserRepository(db, dataRepo)
+	emailPolicy, err := auth.ParseEmailPolicy(getEnv("EMAIL_VERIFICATION_POLICY", string(auth.EmailPolicyNone)))
 	if err != nil {
-		log.Fatalf("Error generating JWT secret: %v", err)
+		log.Fatalf("Error configuring email verification: %v", err)
+	}
+	authz := auth.NewAuthorizer(userRepo, auth.Policy{
+		Email:    emailPolicy,
+		MFARoles: getEnvList("MFA_REQUIRED_ROLES"),
+	}, userRepo)
+	mail, err := newMailer()
+	if err != nil {
+		log.Fatalf("Error configuring mailer: %v", err)
+	}
+	loginThrottle, err := newLoginThrottle(db)
+	if err != nil {
+		log.Fatalf("Error configuring login throttling: %v", err)
+	}
+	sso, err := newSSO()
+	if err != nil {
+		log.Fatalf("Error configuring single sign-on: %v", err)
+	}
+	authenticators, err := newAuthenticators(userRepo, hasher)
+	if err != nil {
+		log.Fatalf("Error configuring authentication backends: %v", err)
+	}
+	userService := user.NewService(userRepo, hasher, passwordPolicy, keys, revocations, authz, mail, loginThrottle, sso, authenticators)
+
+	if len(os.Args) > 1 && os.Args[1] == "create-admin" {
+		createAdmin(userService, os.Args[2:])
+		return
+	}
+
+	dataService := data.NewService(dataRepo, authz, []byte(auditKey))
+	if len(os.Args) > 1 && os.Args[1] == "verify-audit" {
+		verifyAudit(dataService)
+		return
 	}
-	userHandler.RegisterUserHandlers(user.NewService(repository.NewUserRepository(db), jwtSecret))
-	dataHandler.RegisterDataHandlers(data.NewService(dataRepository.NewDataRepository(db), jwtSecret))
+
+	if err := auth.EnsureSigningKey(keys, keyRetention); err != nil {
+		log.Fatalf("Error creating initial JWT signing key: %v", err)
+	}
+	authn := auth.NewAuthenticator(keys, revocations, userRepo, userRepo)
+	userHandler.RegisterUserHandlers(userService, authn)
+	userHandler.RegisterRoleHandlers(role.NewService(userRepo, authz), authn)
+	userHandler.RegisterInstitutionHandlers(institution.NewService(userRepo, authz), authn)
+	dataHandler.RegisterDataHandlers(dataService, authn)
+	dataHandler.RegisterDriveHandlers(drive.NewService(dataRepo, authz), authn)
+	studentRepo := studentRepository.NewStudentRepository(db)
+	studentHandler.RegisterStudentHandlers(student.NewService(studentRepo, authz), authn)
+	applicationRepo := applicationRepository.NewApplicationRepository(db)
+	applicationHandler.RegisterApplicationHandlers(application.NewService(applicationRepo, dataRepo, studentRepo, authz), authn)
+	applicationHandler.RegisterRoundHandlers(round.NewService(applicationRepo, dataRepo, authz), authn)
 
 	port := getEnv("PORT", PORT)
 	log.Printf("Server starting on port %s...", port)
 	log.Fatal(http.ListenAndServe(":"+port, nil))
 }
 
+func newKeyStore(db *sql.DB) (auth.KeyStore, error) {
+	switch backend := getEnv("JWT_KEY_STORE", "db"); backend {
+	case "db":
+		return auth.NewDBKeyStore(db), nil
+	case "file":
+		return auth.NewFileKeyStore(getEnv("JWT_KEY_FILE", "keys/jwt_keys.json")), nil
+	default:
+		return nil, fmt.Errorf("unknown key store %q", backend)
+	}
+}
+
+// rotateKeys implements the rotate-keys admin command. Without -every it
+// rotates once, otherwise it keeps rotating on that schedule.
+func rotateKeys(keys auth.KeyStore, retention time.Duration, args []string) {
+	fs := flag.NewFlagSet("rotate-keys", flag.ExitOnError)
+	every := fs.Duration("every", 0, "rotate on this interval instead of once")
+	fs.DurationVar(&retention, "retention", retention, "how long retired keys keep verifying tokens")
+	fs.Parse(args)
+
+	for {
+		key, err := keys.Rotate(retention)
+		if err != nil {
+			log.Fatalf("Error rotating JWT signing keys: %v", err)
+		}
+		log.Printf("Rotated JWT signing keys, new kid=%s", key.ID)
+
+		if *every <= 0 {
+			return
+		}
+		time.Sleep(*every)
+	}
+}
+
+// createAdmin implements the create-admin command that bootstraps the
+// first account, since creating users over HTTP requires an admin or manager.
+// With -super it creates a super-admin, who can add further institutions.
+func createAdmin(service *user.Service, args []string) {
+	fs := flag.NewFlagSet("create-admin", flag.ExitOnError)
+	name := fs.String("name", "admin", "user name")
+	email := fs.String("email", "", "email address")
+	pass := fs.String("pass", "", "password")
+	institutionID := fs.String("institution", common.DefaultInstitutionID, "id of the institution the admin belongs to")
+	super := fs.Bool("super", false, "create a super-admin managing every institution")
+	fs.Parse(args)
+
+	admin, err := service.CreateAdmin(*institutionID, *name, *email, *pass, *super)
+	if err != nil {
+		log.Fatalf("Error creating admin: %v", err)
+	}
+	log.Printf("Created admin %s with id %s", admin.Email, admin.UserID)
+}
+
+// verifyAudit implements the verify-audit command, which exits non-zero
+// when the company data audit trail has been tampered with.
+func verifyAudit(service *data.Service) {
+	checked, err := service.VerifyAuditTrail()
+	if err != nil {
+		log.Fatalf("Error verifying audit trail after %d entries: %v", checked, err)
+	}
+	log.Printf("Verified %d audit entries", checked)
+}
+
+func newPasswordHasher() (*password.Hasher, error) {
+	cfg := password.DefaultConfig()
+	cfg.Algorithm = getEnv("PASSWORD_HASH_ALGORITHM", cfg.Algorithm)
+	cfg.BcryptCost = getEnvInt("PASSWORD_BCRYPT_COST", cfg.BcryptCost)
+	cfg.Argon2Time = uint32(getEnvInt("PASSWORD_ARGON2_TIME", int(cfg.Argon2Time)))
+	cfg.Argon2Memory = uint32(getEnvInt("PASSWORD_ARGON2_MEMORY_KIB", int(cfg.Argon2Memory)))
+	cfg.Argon2Threads = uint8(getEnvInt("PASSWORD_ARGON2_THREADS", int(cfg.Argon2Threads)))
+	return password.NewHasher(cfg)
+}
+
+func newPasswordPolicy() (*password.Policy, error) {
+	cfg := password.DefaultPolicyConfig()
+	cfg.MinLength = getEnvInt("PASSWORD_MIN_LENGTH", cfg.MinLength)
+	cfg.RequiredClasses = getEnvList("PASSWORD_REQUIRED_CLASSES")
+	cfg.HistorySize = getEnvInt("PASSWORD_HISTORY_SIZE", cfg.HistorySize)
+	cfg.BlocklistFile = getEnv("PASSWORD_BLOCKLIST_FILE", "")
+	return password.NewPolicy(cfg)
+}
+
+func newLoginThrottle(db *sql.DB) (*throttle.Throttler, error) {
+	cfg := throttle.DefaultConfig()
+	cfg.MaxFailures = getEnvInt("LOGIN_MAX_FAILURES", cfg.MaxFailures)
+	cfg.Window = getEnvDuration("LOGIN_FAILURE_WINDOW", cfg.Window)
+	cfg.BaseDelay = getEnvDuration("LOGIN_BACKOFF_BASE", cfg.BaseDelay)
+	cfg.MaxDelay = getEnvDuration("LOGIN_BACKOFF_MAX", cfg.MaxDelay)
+	cfg.LockoutDuration = getEnvDuration("LOGIN_LOCKOUT_DURATION", cfg.LockoutDuration)
+
+	// Records are needed until both their window and lockout have passed
+	retention := max(cfg.Window, cfg.LockoutDuration)
+	switch backend := getEnv("LOGIN_THROTTLE_STORE", "memory"); backend {
+	case "memory":
+		return throttle.New(throttle.NewMemoryStore(retention), cfg), nil
+	case "db":
+		return throttle.New(throttle.NewDBStore(db, retention), cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown login throttle store %q", backend)
+	}
+}
+
+// newSSO configures OIDC single sign-on, which is disabled without OIDC_ISSUER_URL.
+func newSSO() (*user.SSO, error) {
+	issuer := getEnv("OIDC_ISSUER_URL", "")
+	if issuer == "" {
+		return nil, nil
+	}
+
+	roles, err := auth.ParseRoleMapping(getEnv("OIDC_GROUP_ROLES", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg := oidc.Config{
+		IssuerURL:    issuer,
+		ClientID:     getEnv("OIDC_CLIENT_ID", ""),
+		ClientSecret: getEnv("OIDC_CLIENT_SECRET", ""),
+		RedirectURL:  getEnv("OIDC_REDIRECT_URL", "http://localhost:8080/v1/login/oidc/callback"),
+		Scopes:       getEnvList("OIDC_SCOPES"),
+		GroupsClaim:  getEnv("OIDC_GROUPS_CLAIM", "groups"),
+	}
+	if len(cfg.Scopes) == 0 {
+		cfg.Scopes = []string{"openid", "email", "profile"}
+	}
+
+	// The provider may start after us, like the database
+	var provider *oidc.Provider
+	for i := 0; ; i++ {
+		provider, err = oidc.NewProvider(context.Background(), cfg)
+		if err == nil {
+			break
+		}
+		if i == 29 {
+			return nil, err
+		}
+		log.Printf("OIDC provider not ready, waiting... (attempt %d/30), err=%v", i+1, err)
+		time.Sleep(2 * time.Second)
+	}
+
+	return &user.SSO{
+		Provider:    provider,
+		Roles:       roles,
+		DefaultRole: getEnv("OIDC_DEFAULT_ROLE", ""),
+	}, nil
+}
+
+// newAuthenticators returns the password login backends in the order of
+// AUTH_BACKENDS, so "ldap,password" falls back to local passwords for users
+// the directory does not know.
+func newAuthenticators(repo user.Repository, hasher user.PasswordHasher) ([]user.Authenticator, error) {
+	backends := getEnvList("AUTH_BACKENDS")
+	if len(backends) == 0 {
+		backends = []string{"password"}
+	}
+
+	var authenticators []user.Authenticator
+	for _, backend := range backends {
+		switch backend {
+		case "password":
+			authenticators = append(authenticators, user.NewPasswordAuthenticator(repo, hasher))
+		case "ldap":
+			authenticator, err := newLDAPAuthenticator()
+			if err != nil {
+				return nil, err
+			}
+			authenticators = append(authenticators, authenticator)
+		default:
+			return nil, fmt.Errorf("unknown authentication backend %q", backend)
+		}
+	}
+	return authenticators, nil
+}
+
+func newLDAPAuthenticator() (user.Authenticator, error) {
+	roles, err := auth.ParseRoleMapping(getEnv("LDAP_GROUP_ROLES", ""))
+	if err != nil {
+		return nil, err
+	}
+	cfg := ldap.DefaultConfig()
+	cfg.URL = getEnv("LDAP_URL", "")
+	cfg.StartTLS = getEnv("LDAP_START_TLS", "false") == "true"
+	cfg.BindDN = getEnv("LDAP_BIND_DN", "")
+	cfg.BindPassword = getEnv("LDAP_BIND_PASSWORD", "")
+	cfg.BaseDN = getEnv("LDAP_BASE_DN", "")
+	cfg.UserFilter = getEnv("LDAP_USER_FILTER", cfg.UserFilter)
+	cfg.EmailAttribute = getEnv("LDAP_EMAIL_ATTRIBUTE", cfg.EmailAttribute)
+	cfg.NameAttribute = getEnv("LDAP_NAME_ATTRIBUTE", cfg.NameAttribute)
+	cfg.GroupAttribute = getEnv("LDAP_GROUP_ATTRIBUTE", cfg.GroupAttribute)
+	cfg.GroupBaseDN = getEnv("LDAP_GROUP_BASE_DN", "")
+	cfg.GroupFilter = getEnv("LDAP_GROUP_FILTER", cfg.GroupFilter)
+	if cfg.URL == "" || cfg.BaseDN == "" {
+		return nil, fmt.Errorf("LDAP_URL and LDAP_BASE_DN are required for the ldap backend")
+	}
+	if strings.Count(cfg.UserFilter, "%s") != 1 {
+		return nil, fmt.Errorf("LDAP_USER_FILTER must contain %%s once")
+	}
+
+	return user.NewDirectoryAuthenticator("ldap", ldap.NewDirectory(cfg), roles, getEnv("LDAP_DEFAULT_ROLE", "")), nil
+}
+
+func newMailer() (mailer.Mailer, error) {
+	switch driver := getEnv("MAIL_DRIVER", "file"); driver {
+	case "smtp":
+		return mailer.NewSMTPMailer(mailer.SMTPConfig{
+			Host:     getEnv("SMTP_HOST", "localhost"),
+			Port:     getEnvInt("SMTP_PORT", 587),
+			Username: getEnv("SMTP_USERNAME", ""),
+			Password: getEnv("SMTP_PASSWORD", ""),
+			From:     getEnv("MAIL_FROM", "no-reply@localhost"),
+		}), nil
+	case "file":
+		// Without MAIL_FILE messages go to the log
+		return mailer.NewFileMailer(getEnv("MAIL_FILE", "")), nil
+	default:
+		return nil, fmt.Errorf("unknown mail driver %q", driver)
+	}
+}
+
 func getEnv(key, defaultVal string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return defaultVal
 }
+
+// getEnvList splits a comma separated variable, ignoring empty items.
+func getEnvList(key string) []string {
+	var list []string
+	for _, item := range strings.Split(os.Getenv(key), ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+func getEnvInt(key string, defaultVal int) int {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultVal
+	}
+	i, err := strconv.Atoi(value)
+	if err != nil {
+		log.Fatalf("Invalid value for %s: %v", key, err)
+	}
+	return i
+}
+
+func getEnvDuration(key string, defaultVal time.Duration) time.Duration {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultVal
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		log.Fatalf("Invalid value for %s: %v", key, err)
+	}
+	return d
+}
@@ -0,0 +1,77 @@
+package auth
+
+import (
+	"log"
+	"time"
+)
+
+// Security event types
+const (
+	EventLoginSucceeded   = "login.succeeded"
+	EventLoginFailed      = "login.failed"
+	EventAccountLocked    = "account.locked"
+	EventRoleChanged      = "role.changed"
+	EventTokenRevoked     = "token.revoked"
+	EventPermissionDenied = "permission.denied"
+)
+
+// EventTypes lists every security event type.
+var EventTypes = []string{
+	EventLoginSucceeded,
+	EventLoginFailed,
+	EventAccountLocked,
+	EventRoleChanged,
+	EventTokenRevoked,
+	EventPermissionDenied,
+}
+
+// Event is an entry of the append-only security audit log.
+type Event struct {
+	EventID int64
+	Type    string
+	// ActorID is the user who acted, empty for anonymous callers and the system
+	ActorID string
+	// SubjectID is the user acted upon, empty when no account matched
+	SubjectID string
+	// InstitutionID is the tenant the event belongs to, empty when unknown
+	InstitutionID string
+	// Email is the address a login was attempted with
+	Email string
+	IP    string
+	// Detail describes the event, such as the permission that was denied
+	Detail    string
+	CreatedAt time.Time
+}
+
+// EventFilter selects a page of events of one institution, newest first.
+// Other zero fields match everything.
+type EventFilter struct {
+	InstitutionID string
+	Type          string
+	ActorID       string
+	SubjectID     string
+	Email         string
+	IP            string
+	From          *time.Time
+	To            *time.Time
+	// Before continues a listing after the event with this ID
+	Before int64
+	Limit  int
+}
+
+// EventRecorder appends events to the security audit log.
+type EventRecorder interface {
+	RecordEvent(event *Event) error
+}
+
+// RecordEvent stamps event and appends it to recorder, which may be nil.
+// Failures are only logged: losing an audit entry must not fail the request.
+func RecordEvent(recorder EventRecorder, event Event) {
+	if recorder == nil {
+		return
+	}
+	event.CreatedAt = time.Now()
+	if err := recorder.RecordEvent(&event); err != nil {
+		log.Printf("unable to record %s event, err=%v", event.Type, err)
+	}
+}
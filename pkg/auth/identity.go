@@ -0,0 +1,16 @@
+package auth
+
+import "errors"
+
+// ErrInvalidCredentials is returned by directories that reject a password.
+var ErrInvalidCredentials = errors.New("directory rejected the credentials")
+
+// Identity is a user vouched for by an external identity provider or
+// directory, to be matched with a local user by email.
+type Identity struct {
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Name          string
+	Groups        []string
+}
@@ -0,0 +1,100 @@
+package auth
+
+import (
+	"crypto/rand"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Key statuses
+const (
+	KeyActive  = "active"
+	KeyRetired = "retired"
+)
+
+const keySecretLen = 32
+
+var (
+	ErrKeyNotFound  = errors.New("signing key not found")
+	ErrNoSigningKey = errors.New("no active signing key")
+	ErrMissingKid   = errors.New("token has no kid header")
+)
+
+// Key is an HMAC secret identified by the kid header of the tokens it signs.
+// Active keys sign new tokens, retired keys only verify tokens issued before
+// the last rotation.
+type Key struct {
+	ID        string     `json:"kid"`
+	Secret    []byte     `json:"secret"`
+	Status    string     `json:"status"`
+	CreatedAt time.Time  `json:"created_at"`
+	RetiredAt *time.Time `json:"retired_at,omitempty"`
+}
+
+// KeyStore holds the JWT signing keys shared by every replica.
+type KeyStore interface {
+	// SigningKey returns the newest active key.
+	SigningKey() (*Key, error)
+	// Key returns the active or retired key with the given kid.
+	Key(kid string) (*Key, error)
+	// Rotate adds a new active key, retires the previous active keys and
+	// drops keys that have been retired for longer than retention.
+	Rotate(retention time.Duration) (*Key, error)
+}
+
+func NewKey() (*Key, error) {
+	secret := make([]byte, keySecretLen)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &Key{
+		ID:        uuid.NewString(),
+		Secret:    secret,
+		Status:    KeyActive,
+		CreatedAt: time.Now().UTC(),
+	}, nil
+}
+
+func signingKey(keys []Key) (*Key, error) {
+	var newest *Key
+	for i := range keys {
+		if keys[i].Status != KeyActive {
+			continue
+		}
+		if newest == nil || keys[i].CreatedAt.After(newest.CreatedAt) {
+			newest = &keys[i]
+		}
+	}
+	if newest == nil {
+		return nil, ErrNoSigningKey
+	}
+	return newest, nil
+}
+
+func findKey(keys []Key, kid string) (*Key, error) {
+	for i := range keys {
+		if keys[i].ID == kid {
+			return &keys[i], nil
+		}
+	}
+	return nil, ErrKeyNotFound
+}
+
+// rotateKeys returns the key set after a rotation performed at now.
+func rotateKeys(keys []Key, next *Key, now time.Time, retention time.Duration) []Key {
+	rotated := make([]Key, 0, len(keys)+1)
+	for _, k := range keys {
+		if k.Status == KeyActive {
+			retiredAt := now
+			k.Status = KeyRetired
+			k.RetiredAt = &retiredAt
+		}
+		if k.RetiredAt != nil && now.Sub(*k.RetiredAt) > retention {
+			continue
+		}
+		rotated = append(rotated, k)
+	}
+	return append(rotated, *next)
+}
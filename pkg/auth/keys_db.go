@@ -0,0 +1,131 @@
+package auth
+
+import (
+	"database/sql"
+	"sync"
+	"time"
+)
+
+// keyCacheTTL bounds how long a replica may keep signing with a key that
+// another replica has already rotated out.
+const keyCacheTTL = 30 * time.Second
+
+// keyReloadInterval limits the reloads caused by unknown kids, which any
+// unauthenticated client can send.
+const keyReloadInterval = 5 * time.Second
+
+// DBKeyStore keeps the keys in the jwt_keys table and caches them briefly.
+type DBKeyStore struct {
+	db *sql.DB
+
+	mu       sync.Mutex
+	keys     []Key
+	loadedAt time.Time
+}
+
+func NewDBKeyStore(db *sql.DB) *DBKeyStore {
+	return &DBKeyStore{db: db}
+}
+
+func (s *DBKeyStore) SigningKey() (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if time.Since(s.loadedAt) > keyCacheTTL {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+	}
+	return signingKey(s.keys)
+}
+
+func (s *DBKeyStore) Key(kid string) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	age := time.Since(s.loadedAt)
+	if age <= keyCacheTTL {
+		key, err := findKey(s.keys, kid)
+		if err == nil || age <= keyReloadInterval {
+			return key, err
+		}
+	}
+	// Unknown kid, the key may have been added by another replica
+	if err := s.load(); err != nil {
+		return nil, err
+	}
+	return findKey(s.keys, kid)
+}
+
+func (s *DBKeyStore) Rotate(retention time.Duration) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next, err := NewKey()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC()
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE jwt_keys SET status = ?, retired_at = ?
+		WHERE status = ?;
+	`, KeyRetired, now, KeyActive); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`
+		DELETE FROM jwt_keys WHERE status = ? AND retired_at < ?;
+	`, KeyRetired, now.Add(-retention)); err != nil {
+		return nil, err
+	}
+	if _, err := tx.Exec(`
+		INSERT INTO jwt_keys (kid, secret, status, created_at)
+		VALUES (?, ?, ?, ?);
+	`, next.ID, next.Secret, next.Status, next.CreatedAt); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	// Force the next read to pick up the new key set
+	s.loadedAt = time.Time{}
+	return next, nil
+}
+
+func (s *DBKeyStore) load() error {
+	rows, err := s.db.Query(`
+		SELECT kid, secret, status, created_at, retired_at
+		FROM jwt_keys;
+	`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	var keys []Key
+	for rows.Next() {
+		var key Key
+		var retiredAt sql.NullTime
+		if err := rows.Scan(&key.ID, &key.Secret, &key.Status, &key.CreatedAt, &retiredAt); err != nil {
+			return err
+		}
+		if retiredAt.Valid {
+			key.RetiredAt = &retiredAt.Time
+		}
+		keys = append(keys, key)
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	s.keys = keys
+	s.loadedAt = time.Now()
+	return nil
+}
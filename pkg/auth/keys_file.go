@@ -0,0 +1,126 @@
+package auth
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// FileKeyStore keeps the keys in a JSON file, for example on a volume
+// shared by the replicas. The file is re-read whenever it changes on disk.
+type FileKeyStore struct {
+	path string
+
+	mu      sync.Mutex
+	keys    []Key
+	modTime time.Time
+}
+
+type keyFile struct {
+	Keys []Key `json:"keys"`
+}
+
+func NewFileKeyStore(path string) *FileKeyStore {
+	return &FileKeyStore{path: path}
+}
+
+func (s *FileKeyStore) SigningKey() (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return signingKey(s.keys)
+}
+
+func (s *FileKeyStore) Key(kid string) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+	return findKey(s.keys, kid)
+}
+
+func (s *FileKeyStore) Rotate(retention time.Duration) (*Key, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.reload(); err != nil {
+		return nil, err
+	}
+
+	next, err := NewKey()
+	if err != nil {
+		return nil, err
+	}
+	keys := rotateKeys(s.keys, next, time.Now().UTC(), retention)
+	if err := s.write(keys); err != nil {
+		return nil, err
+	}
+	s.keys = keys
+	return next, nil
+}
+
+// reload reads the file if it changed since the last read. A missing file is an empty key set.
+func (s *FileKeyStore) reload() error {
+	info, err := os.Stat(s.path)
+	if errors.Is(err, fs.ErrNotExist) {
+		s.keys = nil
+		s.modTime = time.Time{}
+		return nil
+	} else if err != nil {
+		return err
+	}
+	if info.ModTime().Equal(s.modTime) {
+		return nil
+	}
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		return err
+	}
+	var f keyFile
+	if err := json.Unmarshal(raw, &f); err != nil {
+		return err
+	}
+	s.keys = f.Keys
+	s.modTime = info.ModTime()
+	return nil
+}
+
+// write replaces the file atomically so readers never see a partial key set.
+func (s *FileKeyStore) write(keys []Key) error {
+	raw, err := json.MarshalIndent(keyFile{Keys: keys}, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(s.path), ".jwt-keys-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Chmod(0o600); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.path)
+}
@@ -0,0 +1,121 @@
+package auth
+
+import (
+	"backend/pkg/common"
+	"errors"
+	"log"
+	"net/http"
+	"strings"
+	"time"
+)
+
+var (
+	ErrInvalidToken    = errors.New("invalid token")
+	ErrRevokedToken    = errors.New("token has been revoked")
+	ErrUnauthenticated = errors.New("authentication required")
+)
+
+// Authenticator turns bearer tokens into principals.
+type Authenticator struct {
+	keys        KeyStore
+	revocations RevocationList
+	apiKeys     APIKeyStore
+	sessions    SessionTracker
+}
+
+// NewAuthenticator accepts JWTs signed with keys and, unless apiKeys is nil,
+// API keys as well. sessions may be nil when last seen times are not tracked.
+func NewAuthenticator(keys KeyStore, revocations RevocationList, apiKeys APIKeyStore, sessions SessionTracker) *Authenticator {
+	return &Authenticator{
+		keys:        keys,
+		revocations: revocations,
+		apiKeys:     apiKeys,
+		sessions:    sessions,
+	}
+}
+
+// Authenticate verifies the token signature, expiry and revocation status.
+// Tokens starting with APIKeyPrefix are checked as API keys instead.
+func (a *Authenticator) Authenticate(tokenString string) (*Principal, error) {
+	if isAPIKey(tokenString) {
+		return a.authenticateAPIKey(tokenString)
+	}
+
+	claims, err := ParseToken(a.keys, tokenString)
+	if err != nil {
+		log.Printf("unable to parse JWT, err=%v", err)
+		return nil, ErrInvalidToken
+	}
+
+	principal := &Principal{}
+	principal.UserID, _ = claims["user_id"].(string)
+	principal.UserName, _ = claims["user_name"].(string)
+	principal.Email, _ = claims["email"].(string)
+	principal.EmailVerified, _ = claims["email_verified"].(bool)
+	principal.MFA, _ = claims["mfa"].(bool)
+	principal.Role, _ = claims["role"].(string)
+	principal.SessionID, _ = claims["sid"].(string)
+	principal.TokenID, _ = claims["jti"].(string)
+	principal.InstitutionID, _ = claims["institution_id"].(string)
+	expiresAt, err := claims.GetExpirationTime()
+	if err != nil || expiresAt == nil {
+		return nil, ErrInvalidToken
+	}
+	principal.ExpiresAt = expiresAt.Time
+
+	if principal.UserID == "" || principal.Role == "" || principal.TokenID == "" || principal.SessionID == "" ||
+		principal.InstitutionID == "" {
+		return nil, ErrInvalidToken
+	}
+
+	revoked, err := a.revocations.IsRevoked(principal.TokenID, principal.SessionID)
+	if err != nil {
+		log.Printf("unable to check token revocation, err=%v", err)
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrRevokedToken
+	}
+
+	if a.sessions != nil {
+		// A failed write only loses the last seen time, the token itself is valid
+		now := time.Now()
+		if err := a.sessions.TouchSession(principal.SessionID, now, now.Add(-sessionTouchInterval)); err != nil {
+			log.Printf("unable to record use of session %s, err=%v", principal.SessionID, err)
+		}
+	}
+
+	return principal, nil
+}
+
+// Middleware authenticates the Authorization: Bearer header and stores the
+// principal in the request context. Requests without the header continue
+// anonymously and the usecase decides whether that is allowed; a header
+// with a bad token is rejected with 401.
+func (a *Authenticator) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tokenString, ok := strings.CutPrefix(header, "Bearer ")
+		if !ok || tokenString == "" {
+			http.Error(w, "invalid authorization header", http.StatusUnauthorized)
+			return
+		}
+
+		principal, err := a.Authenticate(tokenString)
+		if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrRevokedToken) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, "unable to authenticate request", http.StatusInternalServerError)
+			return
+		}
+
+		principal.IP = common.ClientIP(r)
+		next.ServeHTTP(w, r.WithContext(NewContext(r.Context(), principal)))
+	})
+}
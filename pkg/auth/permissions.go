@@ -0,0 +1,128 @@
+package auth
+
+import (
+	"backend/pkg/common"
+	"errors"
+	"log"
+	"slices"
+	"sync"
+	"time"
+)
+
+var ErrForbidden = errors.New("permission denied: insufficient role")
+
+// permissionCacheTTL bounds how long a replica keeps using permissions
+// that were changed through another replica.
+const permissionCacheTTL = 30 * time.Second
+
+// PermissionStore resolves the permissions granted to a role held by users
+// of an institution.
+type PermissionStore interface {
+	GetRolePermissions(institutionID, role string) ([]string, error)
+}
+
+type cachedPermissions struct {
+	permissions []string
+	loadedAt    time.Time
+}
+
+// Authorizer checks principals against the role permissions of a PermissionStore.
+type Authorizer struct {
+	store  PermissionStore
+	policy Policy
+	events EventRecorder
+
+	mu    sync.Mutex
+	cache map[string]cachedPermissions
+}
+
+// NewAuthorizer records denied decisions to events, unless it is nil.
+func NewAuthorizer(store PermissionStore, policy Policy, events EventRecorder) *Authorizer {
+	return &Authorizer{
+		store:  store,
+		policy: policy,
+		events: events,
+		cache:  make(map[string]cachedPermissions),
+	}
+}
+
+// EmailPolicy returns the email verification policy enforced on principals.
+func (a *Authorizer) EmailPolicy() EmailPolicy {
+	return a.policy.Email
+}
+
+// RequiresMFA reports whether users with role must pass a second factor.
+func (a *Authorizer) RequiresMFA(role string) bool {
+	return slices.Contains(a.policy.MFARoles, role)
+}
+
+// Authorize returns ErrUnauthenticated for anonymous callers and ErrForbidden
+// when the principal's role is not granted action, or action is outside the
+// scopes of the API key they used. Under EmailPolicyPrivileged
+// unverified principals get ErrEmailUnverified for anything but reads, and
+// principals of MFA roles get ErrMFARequired until they pass a second factor.
+func (a *Authorizer) Authorize(principal *Principal, action string) error {
+	if principal == nil {
+		return ErrUnauthenticated
+	}
+
+	permissions, err := a.permissions(principal.InstitutionID, principal.Role)
+	if err != nil {
+		log.Printf("unable to resolve permissions of role '%s', err=%v", principal.Role, err)
+		return err
+	}
+	if !slices.Contains(permissions, action) {
+		return a.deny(principal, action, ErrForbidden, "role '"+principal.Role+"' lacks the permission")
+	}
+	if principal.IsAPIKey() && !slices.Contains(principal.Scopes, action) {
+		return a.deny(principal, action, ErrForbidden, "outside the scopes of API key "+principal.APIKeyID)
+	}
+	if a.policy.Email == EmailPolicyPrivileged && !principal.EmailVerified && !slices.Contains(common.ReadOnlyActions, action) {
+		return a.deny(principal, action, ErrEmailUnverified, "email address not verified")
+	}
+	if !principal.MFA && a.RequiresMFA(principal.Role) {
+		return a.deny(principal, action, ErrMFARequired, "no second factor")
+	}
+	return nil
+}
+
+// deny records a denied decision and returns err.
+func (a *Authorizer) deny(principal *Principal, action string, err error, reason string) error {
+	log.Printf("user %s denied %s: %s", principal.UserID, action, reason)
+	RecordEvent(a.events, Event{
+		Type:          EventPermissionDenied,
+		ActorID:       principal.UserID,
+		InstitutionID: principal.InstitutionID,
+		IP:            principal.IP,
+		Detail:        action + ": " + reason,
+	})
+	return err
+}
+
+// Invalidate drops cached permissions after roles were changed.
+func (a *Authorizer) Invalidate() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	a.cache = make(map[string]cachedPermissions)
+}
+
+func (a *Authorizer) permissions(institutionID, role string) ([]string, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	key := institutionID + "/" + role
+	if cached, ok := a.cache[key]; ok && time.Since(cached.loadedAt) <= permissionCacheTTL {
+		return cached.permissions, nil
+	}
+
+	permissions, err := a.store.GetRolePermissions(institutionID, role)
+	if err != nil {
+		return nil, err
+	}
+	a.cache[key] = cachedPermissions{
+		permissions: permissions,
+		loadedAt:    time.Now(),
+	}
+	return permissions, nil
+}
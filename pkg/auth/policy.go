@@ -0,0 +1,39 @@
+package auth
+
+import (
+	"fmt"
+)
+
+// Policy holds the account requirements enforced on top of role permissions.
+type Policy struct {
+	Email EmailPolicy
+	// MFARoles lists the roles that may only act through sessions that
+	// passed a second factor
+	MFARoles []string
+}
+
+// EmailPolicy decides what users may do before verifying their email address.
+type EmailPolicy string
+
+const (
+	// EmailPolicyNone does not require verified email addresses
+	EmailPolicyNone EmailPolicy = "none"
+	// EmailPolicyLogin refuses to log in users with unverified addresses
+	EmailPolicyLogin EmailPolicy = "login"
+	// EmailPolicyPrivileged lets unverified users log in but only perform read-only actions
+	EmailPolicyPrivileged EmailPolicy = "privileged"
+)
+
+var (
+	ErrEmailUnverified = fmt.Errorf("%w: email address is not verified", ErrForbidden)
+	ErrMFARequired     = fmt.Errorf("%w: two-factor authentication required", ErrForbidden)
+)
+
+func ParseEmailPolicy(s string) (EmailPolicy, error) {
+	switch policy := EmailPolicy(s); policy {
+	case EmailPolicyNone, EmailPolicyLogin, EmailPolicyPrivileged:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown email verification policy %q", s)
+	}
+}
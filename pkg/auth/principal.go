@@ -0,0 +1,46 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Principal is the authenticated caller of a request.
+type Principal struct {
+	UserID        string
+	UserName      string
+	Email         string
+	EmailVerified bool
+	MFA           bool
+	Role          string
+	SessionID     string
+	TokenID       string
+	ExpiresAt     time.Time
+	// InstitutionID is the tenant the caller acts in. Super-admins can
+	// switch it, everyone else is bound to the institution of their account
+	InstitutionID string
+	// IP the request came from
+	IP string
+	// Set when the caller used an API key, which is only granted the
+	// permissions of its owner's role that are also among its scopes
+	APIKeyID string
+	Scopes   []string
+}
+
+// IsAPIKey reports whether the caller authenticated with an API key rather
+// than by logging in.
+func (p *Principal) IsAPIKey() bool {
+	return p.APIKeyID != ""
+}
+
+type principalKey struct{}
+
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal stored by Middleware, or nil for anonymous requests.
+func FromContext(ctx context.Context) *Principal {
+	principal, _ := ctx.Value(principalKey{}).(*Principal)
+	return principal
+}
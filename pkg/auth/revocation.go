@@ -0,0 +1,49 @@
+package auth
+
+import (
+	"database/sql"
+	"time"
+)
+
+// RevocationList answers whether a token was killed before it expired,
+// either on its own (by jti) or together with its session.
+type RevocationList interface {
+	IsRevoked(jti, sessionID string) (bool, error)
+	Revoke(jti string, expiresAt time.Time) error
+}
+
+// DBRevocationList reads the revoked_tokens and sessions tables.
+type DBRevocationList struct {
+	db *sql.DB
+}
+
+func NewDBRevocationList(db *sql.DB) *DBRevocationList {
+	return &DBRevocationList{db: db}
+}
+
+func (l *DBRevocationList) IsRevoked(jti, sessionID string) (bool, error) {
+	var revoked bool
+	err := l.db.QueryRow(`
+		SELECT
+			EXISTS(SELECT 1 FROM revoked_tokens WHERE jti = ?)
+			OR EXISTS(SELECT 1 FROM sessions WHERE session_id = ? AND revoked_at IS NOT NULL);
+	`, jti, sessionID).Scan(&revoked)
+	if err != nil {
+		return false, err
+	}
+	return revoked, nil
+}
+
+// Revoke records jti until expiresAt, after which the token is rejected as expired anyway.
+func (l *DBRevocationList) Revoke(jti string, expiresAt time.Time) error {
+	if _, err := l.db.Exec(`
+		INSERT IGNORE INTO revoked_tokens (jti, expires_at)
+		VALUES (?, ?);
+	`, jti, expiresAt); err != nil {
+		return err
+	}
+
+	// Expired entries are no longer needed
+	_, err := l.db.Exec("DELETE FROM revoked_tokens WHERE expires_at < ?;", time.Now())
+	return err
+}
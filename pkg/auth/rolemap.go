@@ -0,0 +1,45 @@
+package auth
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// GroupRole grants Role to members of Group in an external directory.
+type GroupRole struct {
+	Group string
+	Role  string
+}
+
+// RoleMapping maps the groups of an identity provider or directory to our
+// roles. The first entry whose group the user belongs to wins, so more
+// privileged groups should come first.
+type RoleMapping []GroupRole
+
+// ParseRoleMapping parses a comma separated list of group=role pairs.
+func ParseRoleMapping(s string) (RoleMapping, error) {
+	var mapping RoleMapping
+	for _, pair := range strings.Split(s, ",") {
+		if pair = strings.TrimSpace(pair); pair == "" {
+			continue
+		}
+		group, role, ok := strings.Cut(pair, "=")
+		group, role = strings.TrimSpace(group), strings.TrimSpace(role)
+		if !ok || group == "" || role == "" {
+			return nil, fmt.Errorf("invalid group to role mapping %q", pair)
+		}
+		mapping = append(mapping, GroupRole{Group: group, Role: role})
+	}
+	return mapping, nil
+}
+
+// Role returns the role of the first mapped group found in groups.
+func (m RoleMapping) Role(groups []string) (string, bool) {
+	for _, gr := range m {
+		if slices.Contains(groups, gr.Group) {
+			return gr.Role, true
+		}
+	}
+	return "", false
+}
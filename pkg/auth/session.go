@@ -0,0 +1,13 @@
+package auth
+
+import "time"
+
+// sessionTouchInterval limits how often the last seen time of a busy session is written.
+const sessionTouchInterval = time.Minute
+
+// SessionTracker records when the session of a token was last used.
+type SessionTracker interface {
+	// TouchSession records that the session was seen at seenAt, unless it
+	// was already recorded as seen after notBefore.
+	TouchSession(sessionID string, seenAt, notBefore time.Time) error
+}
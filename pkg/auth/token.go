@@ -0,0 +1,57 @@
+package auth
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// SignToken signs claims with the current signing key and records its kid in the header.
+func SignToken(keys KeyStore, claims jwt.Claims) (string, error) {
+	key, err := keys.SigningKey()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	token.Header["kid"] = key.ID
+
+	return token.SignedString(key.Secret)
+}
+
+// ParseToken verifies tokenString with the key named by its kid header.
+func ParseToken(keys KeyStore, tokenString string) (jwt.MapClaims, error) {
+	claims := jwt.MapClaims{}
+	token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+		}
+		kid, ok := token.Header["kid"].(string)
+		if !ok || kid == "" {
+			return nil, ErrMissingKid
+		}
+		key, err := keys.Key(kid)
+		if err != nil {
+			return nil, err
+		}
+		return key.Secret, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if !token.Valid {
+		return nil, errors.New("token is not valid")
+	}
+	return claims, nil
+}
+
+// EnsureSigningKey creates the first key of an empty store.
+func EnsureSigningKey(keys KeyStore, retention time.Duration) error {
+	_, err := keys.SigningKey()
+	if errors.Is(err, ErrNoSigningKey) {
+		_, err = keys.Rotate(retention)
+	}
+	return err
+}
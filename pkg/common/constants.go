@@ -1,7 +1,85 @@
 package common
 
-// Valid user roles
-var ValidRoles = []string{"admin", "manager", "user"}
+// Built-in roles seeded by init.sql, further roles are managed through /v1/roles
+const (
+	RoleSuperAdmin = "superadmin"
+	RoleAdmin      = "admin"
+	RoleManager    = "manager"
+	RoleUser       = "user"
+	RoleStudent    = "student"
+)
 
-// Roles that can create data
-var ValidRolesToCreateData = []string{"admin", "manager"}
+// DefaultInstitutionID is the institution seeded by init.sql. Bootstrapped
+// admins and users provisioned from external directories belong to it.
+const DefaultInstitutionID = "00000000-0000-0000-0000-000000000001"
+
+// Actions checked against the permissions of the caller's role
+const (
+	CompanyCreate  = "company:create"
+	CompanyRead    = "company:read"
+	CompanyUpdate  = "company:update"
+	CompanyApprove = "company:approve"
+	UserCreate     = "user:create"
+	UserRead       = "user:read"
+	UserInvite     = "user:invite"
+	UserUpdate     = "user:update"
+	UserDeactivate = "user:deactivate"
+	UserDelete     = "user:delete"
+	UserUnlock     = "user:unlock"
+	UserSignOut    = "user:signout"
+	RoleRead       = "role:read"
+	RoleManage     = "role:manage"
+	AuditRead      = "audit:read"
+	StudentRead    = "student:read"
+	StudentVerify  = "student:verify"
+	// StudentProfile lets students maintain their own profile
+	StudentProfile = "student:profile"
+	DriveRead      = "drive:read"
+	DriveManage    = "drive:manage"
+	// ApplicationApply lets students apply to drives and withdraw
+	ApplicationApply = "application:apply"
+	ApplicationRead  = "application:read"
+	// RoundManage covers both the rounds of a drive and their results
+	RoundManage = "round:manage"
+	// InstitutionManage spans every institution, so only super-admins hold it
+	InstitutionManage = "institution:manage"
+)
+
+// Actions lists every permission that can be granted to a role
+var Actions = []string{
+	CompanyCreate,
+	CompanyRead,
+	CompanyUpdate,
+	CompanyApprove,
+	UserCreate,
+	UserRead,
+	UserInvite,
+	UserUpdate,
+	UserDeactivate,
+	UserDelete,
+	UserUnlock,
+	UserSignOut,
+	RoleRead,
+	RoleManage,
+	AuditRead,
+	StudentRead,
+	StudentVerify,
+	StudentProfile,
+	DriveRead,
+	DriveManage,
+	ApplicationApply,
+	ApplicationRead,
+	RoundManage,
+	InstitutionManage,
+}
+
+// ReadOnlyActions are still allowed to users with an unverified email
+// address under the "privileged" email verification policy
+var ReadOnlyActions = []string{
+	CompanyRead,
+	UserRead,
+	RoleRead,
+	StudentRead,
+	DriveRead,
+	ApplicationRead,
+}
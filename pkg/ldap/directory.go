@@ -0,0 +1,167 @@
+// Package ldap authenticates users against an LDAP or Active Directory
+// server by searching for their entry and binding as it.
+package ldap
+
+import (
+	"backend/pkg/auth"
+	"crypto/tls"
+	"fmt"
+	"strings"
+
+	goldap "github.com/go-ldap/ldap/v3"
+)
+
+type Config struct {
+	// URL of the server, ldap:// or ldaps://
+	URL      string
+	StartTLS bool
+	// Service account used to search for users, anonymous when empty
+	BindDN       string
+	BindPassword string
+	BaseDN       string
+	// UserFilter finds a user by email, %s is replaced by the escaped email
+	UserFilter     string
+	EmailAttribute string
+	NameAttribute  string
+	// Groups are read from GroupAttribute of the user entry (memberOf on
+	// Active Directory) unless GroupBaseDN is set, in which case groups with
+	// GroupFilter matching the user DN are searched there instead
+	GroupAttribute string
+	GroupBaseDN    string
+	GroupFilter    string
+}
+
+func DefaultConfig() Config {
+	return Config{
+		UserFilter:     "(&(objectClass=person)(mail=%s))",
+		EmailAttribute: "mail",
+		NameAttribute:  "cn",
+		GroupAttribute: "memberOf",
+		GroupFilter:    "(&(objectClass=groupOfNames)(member=%s))",
+	}
+}
+
+type Directory struct {
+	cfg Config
+}
+
+func NewDirectory(cfg Config) *Directory {
+	return &Directory{cfg: cfg}
+}
+
+// Authenticate checks pass by binding as the directory entry of email and
+// returns auth.ErrInvalidCredentials when there is no single such entry or
+// the bind fails. Groups are reported by their common name.
+func (d *Directory) Authenticate(email, pass string) (*auth.Identity, error) {
+	// An empty password would be an unauthenticated bind, which succeeds
+	if email == "" || pass == "" {
+		return nil, auth.ErrInvalidCredentials
+	}
+
+	conn, err := d.dial()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if d.cfg.BindDN != "" {
+		if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+			return nil, fmt.Errorf("unable to bind service account, err=%w", err)
+		}
+	}
+
+	attributes := []string{d.cfg.EmailAttribute, d.cfg.NameAttribute}
+	if d.cfg.GroupBaseDN == "" {
+		attributes = append(attributes, d.cfg.GroupAttribute)
+	}
+	result, err := conn.Search(goldap.NewSearchRequest(
+		d.cfg.BaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 2, 0, false,
+		fmt.Sprintf(d.cfg.UserFilter, goldap.EscapeFilter(email)),
+		attributes, nil))
+	if err != nil {
+		return nil, fmt.Errorf("unable to search for user, err=%w", err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, auth.ErrInvalidCredentials
+	}
+	entry := result.Entries[0]
+
+	if err := conn.Bind(entry.DN, pass); goldap.IsErrorWithCode(err, goldap.LDAPResultInvalidCredentials) {
+		return nil, auth.ErrInvalidCredentials
+	} else if err != nil {
+		return nil, fmt.Errorf("unable to bind as user, err=%w", err)
+	}
+
+	groupDNs := entry.GetAttributeValues(d.cfg.GroupAttribute)
+	if d.cfg.GroupBaseDN != "" {
+		// Search groups as the service account, users may not be allowed to
+		if d.cfg.BindDN != "" {
+			if err := conn.Bind(d.cfg.BindDN, d.cfg.BindPassword); err != nil {
+				return nil, fmt.Errorf("unable to bind service account, err=%w", err)
+			}
+		}
+		if groupDNs, err = d.searchGroups(conn, entry.DN); err != nil {
+			return nil, err
+		}
+	}
+
+	identity := &auth.Identity{
+		Subject: entry.DN,
+		Email:   entry.GetAttributeValue(d.cfg.EmailAttribute),
+		// The directory owns its addresses
+		EmailVerified: true,
+		Name:          entry.GetAttributeValue(d.cfg.NameAttribute),
+	}
+	if identity.Email == "" {
+		identity.Email = email
+	}
+	for _, dn := range groupDNs {
+		identity.Groups = append(identity.Groups, commonName(dn))
+	}
+	return identity, nil
+}
+
+func (d *Directory) dial() (*goldap.Conn, error) {
+	conn, err := goldap.DialURL(d.cfg.URL)
+	if err != nil {
+		return nil, fmt.Errorf("unable to connect to %s, err=%w", d.cfg.URL, err)
+	}
+	if d.cfg.StartTLS {
+		host := strings.TrimPrefix(d.cfg.URL, "ldap://")
+		host, _, _ = strings.Cut(host, ":")
+		if err := conn.StartTLS(&tls.Config{ServerName: host}); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("unable to start TLS, err=%w", err)
+		}
+	}
+	return conn, nil
+}
+
+func (d *Directory) searchGroups(conn *goldap.Conn, userDN string) ([]string, error) {
+	result, err := conn.Search(goldap.NewSearchRequest(
+		d.cfg.GroupBaseDN, goldap.ScopeWholeSubtree, goldap.NeverDerefAliases, 0, 0, false,
+		fmt.Sprintf(d.cfg.GroupFilter, goldap.EscapeFilter(userDN)),
+		[]string{"dn"}, nil))
+	if err != nil {
+		return nil, fmt.Errorf("unable to search for groups, err=%w", err)
+	}
+	dns := make([]string, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		dns = append(dns, entry.DN)
+	}
+	return dns, nil
+}
+
+// commonName returns the CN of a group DN, or the DN itself if it has none.
+func commonName(dn string) string {
+	parsed, err := goldap.ParseDN(dn)
+	if err != nil || len(parsed.RDNs) == 0 {
+		return dn
+	}
+	for _, attr := range parsed.RDNs[0].Attributes {
+		if strings.EqualFold(attr.Type, "cn") {
+			return attr.Value
+		}
+	}
+	return dn
+}
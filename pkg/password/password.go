@@ -0,0 +1,203 @@
+package password
+
+import (
+	"crypto/md5"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+
+	"golang.org/x/crypto/argon2"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// Supported hashing algorithms
+const (
+	Bcrypt   = "bcrypt"
+	Argon2id = "argon2id"
+)
+
+var ErrUnknownAlgorithm = errors.New("unknown password hashing algorithm")
+
+// Config holds the tunable parameters of a Hasher.
+type Config struct {
+	Algorithm     string
+	BcryptCost    int
+	Argon2Time    uint32
+	Argon2Memory  uint32 // KiB
+	Argon2Threads uint8
+}
+
+// DefaultConfig returns argon2id with the parameters recommended by RFC 9106.
+func DefaultConfig() Config {
+	return Config{
+		Algorithm:     Argon2id,
+		BcryptCost:    12,
+		Argon2Time:    3,
+		Argon2Memory:  64 * 1024,
+		Argon2Threads: 4,
+	}
+}
+
+const (
+	argon2SaltLen = 16
+	argon2KeyLen  = 32
+)
+
+type Hasher struct {
+	cfg Config
+}
+
+func NewHasher(cfg Config) (*Hasher, error) {
+	switch cfg.Algorithm {
+	case Bcrypt:
+		if cfg.BcryptCost < bcrypt.MinCost || cfg.BcryptCost > bcrypt.MaxCost {
+			return nil, fmt.Errorf("bcrypt cost must be between %d and %d", bcrypt.MinCost, bcrypt.MaxCost)
+		}
+	case Argon2id:
+		if cfg.Argon2Time == 0 || cfg.Argon2Memory == 0 || cfg.Argon2Threads == 0 {
+			return nil, errors.New("argon2id time, memory and threads must be positive")
+		}
+	default:
+		return nil, ErrUnknownAlgorithm
+	}
+	return &Hasher{cfg: cfg}, nil
+}
+
+// Hash encodes plain with the configured algorithm.
+// bcrypt hashes use the usual $2a$ format, argon2id hashes use the PHC string format.
+func (h *Hasher) Hash(plain string) (string, error) {
+	switch h.cfg.Algorithm {
+	case Bcrypt:
+		hash, err := bcrypt.GenerateFromPassword([]byte(plain), h.cfg.BcryptCost)
+		if err != nil {
+			return "", err
+		}
+		return string(hash), nil
+	case Argon2id:
+		salt := make([]byte, argon2SaltLen)
+		if _, err := rand.Read(salt); err != nil {
+			return "", err
+		}
+		key := argon2.IDKey([]byte(plain), salt, h.cfg.Argon2Time, h.cfg.Argon2Memory, h.cfg.Argon2Threads, argon2KeyLen)
+		return fmt.Sprintf("$argon2id$v=%d$m=%d,t=%d,p=%d$%s$%s",
+			argon2.Version,
+			h.cfg.Argon2Memory,
+			h.cfg.Argon2Time,
+			h.cfg.Argon2Threads,
+			base64.RawStdEncoding.EncodeToString(salt),
+			base64.RawStdEncoding.EncodeToString(key)), nil
+	default:
+		return "", ErrUnknownAlgorithm
+	}
+}
+
+// Verify reports whether plain matches the stored value and whether the stored
+// value should be replaced by a fresh Hash. Stored values that are neither bcrypt
+// nor argon2id are treated as legacy rows: plaintext, or an unsalted hex
+// MD5/SHA-1/SHA-256 digest. They always need a rehash.
+func (h *Hasher) Verify(stored, plain string) (match bool, needsRehash bool, err error) {
+	switch {
+	case strings.HasPrefix(stored, "$argon2id$"):
+		p, salt, key, err := decodeArgon2id(stored)
+		if err != nil {
+			return false, false, err
+		}
+		candidate := argon2.IDKey([]byte(plain), salt, p.time, p.memory, p.threads, uint32(len(key)))
+		if subtle.ConstantTimeCompare(candidate, key) != 1 {
+			return false, false, nil
+		}
+		return true, h.cfg.Algorithm != Argon2id ||
+			p.time < h.cfg.Argon2Time ||
+			p.memory < h.cfg.Argon2Memory ||
+			p.threads < h.cfg.Argon2Threads, nil
+	case isBcrypt(stored):
+		err := bcrypt.CompareHashAndPassword([]byte(stored), []byte(plain))
+		if errors.Is(err, bcrypt.ErrMismatchedHashAndPassword) {
+			return false, false, nil
+		} else if err != nil {
+			return false, false, err
+		}
+		cost, err := bcrypt.Cost([]byte(stored))
+		if err != nil {
+			return false, false, err
+		}
+		return true, h.cfg.Algorithm != Bcrypt || cost < h.cfg.BcryptCost, nil
+	default:
+		return verifyLegacy(stored, plain), true, nil
+	}
+}
+
+func isBcrypt(stored string) bool {
+	return strings.HasPrefix(stored, "$2a$") ||
+		strings.HasPrefix(stored, "$2b$") ||
+		strings.HasPrefix(stored, "$2y$")
+}
+
+// verifyLegacy checks passwords stored before hashing was introduced: hex
+// MD5, SHA-1 or SHA-256 digests, otherwise plaintext. Values shaped like a
+// digest are only ever compared as one, so a leaked digest is not accepted
+// as the password.
+func verifyLegacy(stored, plain string) bool {
+	var digest []byte
+	switch len(stored) {
+	case hex.EncodedLen(md5.Size):
+		sum := md5.Sum([]byte(plain))
+		digest = sum[:]
+	case hex.EncodedLen(sha1.Size):
+		sum := sha1.Sum([]byte(plain))
+		digest = sum[:]
+	case hex.EncodedLen(sha256.Size):
+		sum := sha256.Sum256([]byte(plain))
+		digest = sum[:]
+	}
+	if digest != nil {
+		if storedDigest, err := hex.DecodeString(strings.ToLower(stored)); err == nil {
+			return subtle.ConstantTimeCompare(storedDigest, digest) == 1
+		}
+	}
+	return subtle.ConstantTimeCompare([]byte(stored), []byte(plain)) == 1
+}
+
+type argon2Params struct {
+	time    uint32
+	memory  uint32
+	threads uint8
+}
+
+func decodeArgon2id(encoded string) (*argon2Params, []byte, []byte, error) {
+	// $argon2id$v=19$m=65536,t=3,p=4$<salt>$<key>
+	parts := strings.Split(encoded, "$")
+	if len(parts) != 6 {
+		return nil, nil, nil, errors.New("invalid argon2id hash format")
+	}
+
+	var version int
+	if _, err := fmt.Sscanf(parts[2], "v=%d", &version); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id version, err=%w", err)
+	}
+	if version != argon2.Version {
+		return nil, nil, nil, fmt.Errorf("unsupported argon2id version %d", version)
+	}
+
+	var p argon2Params
+	if _, err := fmt.Sscanf(parts[3], "m=%d,t=%d,p=%d", &p.memory, &p.time, &p.threads); err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id parameters, err=%w", err)
+	}
+
+	salt, err := base64.RawStdEncoding.DecodeString(parts[4])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id salt, err=%w", err)
+	}
+	key, err := base64.RawStdEncoding.DecodeString(parts[5])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("invalid argon2id key, err=%w", err)
+	}
+
+	return &p, salt, key, nil
+}
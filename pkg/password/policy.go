@@ -0,0 +1,138 @@
+package password
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Character classes a policy can require
+const (
+	ClassLower  = "lower"
+	ClassUpper  = "upper"
+	ClassDigit  = "digit"
+	ClassSymbol = "symbol"
+)
+
+var classes = []string{ClassLower, ClassUpper, ClassDigit, ClassSymbol}
+
+var classNames = map[string]string{
+	ClassLower:  "a lowercase letter",
+	ClassUpper:  "an uppercase letter",
+	ClassDigit:  "a digit",
+	ClassSymbol: "a symbol",
+}
+
+// PolicyConfig holds the rules new passwords must follow.
+type PolicyConfig struct {
+	MinLength       int
+	RequiredClasses []string
+	// HistorySize is how many of the user's previous passwords, including
+	// the current one, cannot be chosen again
+	HistorySize int
+	// BlocklistFile lists common or breached passwords, one per line.
+	// Lines starting with # are comments. Matching ignores case.
+	BlocklistFile string
+}
+
+// DefaultPolicyConfig follows NIST SP 800-63B: a minimum length and a
+// blocklist rather than composition rules.
+func DefaultPolicyConfig() PolicyConfig {
+	return PolicyConfig{
+		MinLength: 8,
+	}
+}
+
+type Policy struct {
+	cfg       PolicyConfig
+	blocklist map[string]struct{}
+}
+
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	if cfg.MinLength < 1 {
+		return nil, fmt.Errorf("minimum password length must be positive")
+	}
+	if cfg.HistorySize < 0 {
+		return nil, fmt.Errorf("password history size cannot be negative")
+	}
+	for _, class := range cfg.RequiredClasses {
+		if !slices.Contains(classes, class) {
+			return nil, fmt.Errorf("unknown character class %q, expected one of %s", class, strings.Join(classes, ", "))
+		}
+	}
+
+	policy := &Policy{cfg: cfg, blocklist: make(map[string]struct{})}
+	if cfg.BlocklistFile != "" {
+		if err := policy.loadBlocklist(cfg.BlocklistFile); err != nil {
+			return nil, err
+		}
+	}
+	return policy, nil
+}
+
+func (p *Policy) loadBlocklist(path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("unable to open password blocklist, err=%w", err)
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		p.blocklist[strings.ToLower(line)] = struct{}{}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("unable to read password blocklist, err=%w", err)
+	}
+	return nil
+}
+
+// HistorySize is how many previous passwords cannot be reused.
+func (p *Policy) HistorySize() int {
+	return p.cfg.HistorySize
+}
+
+// Check returns every rule plain breaks, or nothing if it is acceptable.
+// Reuse of previous passwords is checked by the caller against HistorySize.
+func (p *Policy) Check(plain string) []string {
+	if plain == "" {
+		return []string{"cannot be empty"}
+	}
+
+	var problems []string
+	if utf8.RuneCountInString(plain) < p.cfg.MinLength {
+		problems = append(problems, fmt.Sprintf("must be at least %d characters long", p.cfg.MinLength))
+	}
+
+	present := make(map[string]bool)
+	for _, r := range plain {
+		switch {
+		case unicode.IsLower(r):
+			present[ClassLower] = true
+		case unicode.IsUpper(r):
+			present[ClassUpper] = true
+		case unicode.IsDigit(r):
+			present[ClassDigit] = true
+		default:
+			present[ClassSymbol] = true
+		}
+	}
+	for _, class := range p.cfg.RequiredClasses {
+		if !present[class] {
+			problems = append(problems, "must contain "+classNames[class])
+		}
+	}
+
+	if _, blocked := p.blocklist[strings.ToLower(plain)]; blocked {
+		problems = append(problems, "is too common, it appears in lists of breached passwords")
+	}
+	return problems
+}
@@ -0,0 +1,88 @@
+package throttle
+
+import (
+	"database/sql"
+	"time"
+)
+
+// DBStore keeps records in the login_attempts table so that every replica
+// sees the same failures.
+type DBStore struct {
+	db        *sql.DB
+	retention time.Duration
+}
+
+// NewDBStore returns a store that purges records untouched for retention,
+// which should be at least the longer of the failure window and lockout duration.
+func NewDBStore(db *sql.DB, retention time.Duration) *DBStore {
+	return &DBStore{db: db, retention: retention}
+}
+
+func (s *DBStore) Get(key string) (Record, error) {
+	var record Record
+	var lockedUntil sql.NullTime
+	err := s.db.QueryRow(`
+		SELECT failures, first_failure_at, last_failure_at, locked_until
+		FROM login_attempts WHERE throttle_key = ?;
+	`, key).Scan(&record.Failures, &record.FirstFailureAt, &record.LastFailureAt, &lockedUntil)
+	if err == sql.ErrNoRows {
+		return Record{}, nil
+	} else if err != nil {
+		return Record{}, err
+	}
+	if lockedUntil.Valid {
+		record.LockedUntil = &lockedUntil.Time
+	}
+	return record, nil
+}
+
+func (s *DBStore) Update(key string, fn func(*Record)) (Record, error) {
+	now := time.Now()
+	if _, err := s.db.Exec(`
+		DELETE FROM login_attempts
+		WHERE last_failure_at < ? AND (locked_until IS NULL OR locked_until < ?);
+	`, now.Add(-s.retention), now); err != nil {
+		return Record{}, err
+	}
+
+	tx, err := s.db.Begin()
+	if err != nil {
+		return Record{}, err
+	}
+	defer tx.Rollback()
+
+	// Make sure the row exists so that concurrent updates queue on its lock
+	if _, err := tx.Exec(`
+		INSERT IGNORE INTO login_attempts (throttle_key, failures, first_failure_at, last_failure_at)
+		VALUES (?, 0, ?, ?);
+	`, key, now, now); err != nil {
+		return Record{}, err
+	}
+
+	var record Record
+	var lockedUntil sql.NullTime
+	if err := tx.QueryRow(`
+		SELECT failures, first_failure_at, last_failure_at, locked_until
+		FROM login_attempts WHERE throttle_key = ? FOR UPDATE;
+	`, key).Scan(&record.Failures, &record.FirstFailureAt, &record.LastFailureAt, &lockedUntil); err != nil {
+		return Record{}, err
+	}
+	if lockedUntil.Valid {
+		record.LockedUntil = &lockedUntil.Time
+	}
+
+	fn(&record)
+
+	if _, err := tx.Exec(`
+		UPDATE login_attempts SET failures = ?, first_failure_at = ?, last_failure_at = ?, locked_until = ?
+		WHERE throttle_key = ?;
+	`, record.Failures, record.FirstFailureAt, record.LastFailureAt, record.LockedUntil, key); err != nil {
+		return Record{}, err
+	}
+	return record, tx.Commit()
+}
+
+func (s *DBStore) Delete(key string) error {
+	_, err := s.db.Exec("DELETE FROM login_attempts WHERE throttle_key = ?;", key)
+	return err
+}
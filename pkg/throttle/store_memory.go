@@ -0,0 +1,64 @@
+package throttle
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore keeps records in process memory, for single instance deployments.
+type MemoryStore struct {
+	retention time.Duration
+
+	mu      sync.Mutex
+	records map[string]Record
+	pruned  time.Time
+}
+
+// NewMemoryStore returns a store that forgets records untouched for retention,
+// which should be at least the longer of the failure window and lockout duration.
+func NewMemoryStore(retention time.Duration) *MemoryStore {
+	return &MemoryStore{
+		retention: retention,
+		records:   make(map[string]Record),
+	}
+}
+
+func (s *MemoryStore) Get(key string) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.records[key], nil
+}
+
+func (s *MemoryStore) Update(key string, fn func(*Record)) (Record, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.prune()
+	record := s.records[key]
+	fn(&record)
+	s.records[key] = record
+	return record, nil
+}
+
+func (s *MemoryStore) Delete(key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.records, key)
+	return nil
+}
+
+// prune drops stale records at most once per retention period.
+func (s *MemoryStore) prune() {
+	now := time.Now()
+	if now.Sub(s.pruned) < s.retention {
+		return
+	}
+	for key, record := range s.records {
+		if now.Sub(record.LastFailureAt) > s.retention && (record.LockedUntil == nil || now.After(*record.LockedUntil)) {
+			delete(s.records, key)
+		}
+	}
+	s.pruned = now
+}
@@ -0,0 +1,150 @@
+package throttle
+
+import (
+	"fmt"
+	"time"
+)
+
+// Record tracks the recent failed attempts made with one key, such as an
+// email address or a client IP.
+type Record struct {
+	Failures       int
+	FirstFailureAt time.Time
+	LastFailureAt  time.Time
+	LockedUntil    *time.Time
+}
+
+// Store keeps the records of a Throttler.
+type Store interface {
+	// Get returns the record of key, or an empty record if there is none.
+	Get(key string) (Record, error)
+	// Update applies fn to the record of key atomically and stores the result.
+	Update(key string, fn func(*Record)) (Record, error)
+	// Delete forgets the record of key.
+	Delete(key string) error
+}
+
+type Config struct {
+	// MaxFailures within Window lock a lockable key
+	MaxFailures int
+	Window      time.Duration
+	// Failures after the first FreeFailures double the wait before the
+	// next attempt, starting at BaseDelay
+	FreeFailures    int
+	BaseDelay       time.Duration
+	MaxDelay        time.Duration
+	LockoutDuration time.Duration
+}
+
+func DefaultConfig() Config {
+	return Config{
+		MaxFailures:     10,
+		Window:          15 * time.Minute,
+		FreeFailures:    3,
+		BaseDelay:       time.Second,
+		MaxDelay:        5 * time.Minute,
+		LockoutDuration: 30 * time.Minute,
+	}
+}
+
+// LimitedError is returned for attempts made too early after a failure or
+// while the key is locked.
+type LimitedError struct {
+	Locked     bool
+	RetryAfter time.Duration
+}
+
+func (e *LimitedError) Error() string {
+	if e.Locked {
+		return fmt.Sprintf("account locked after too many failed attempts, retry in %s", e.RetryAfter.Round(time.Second))
+	}
+	return fmt.Sprintf("too many failed attempts, retry in %s", e.RetryAfter.Round(time.Second))
+}
+
+// Throttler applies exponential backoff to keys with failed attempts and
+// locks keys that keep failing.
+type Throttler struct {
+	store Store
+	cfg   Config
+}
+
+func New(store Store, cfg Config) *Throttler {
+	return &Throttler{store: store, cfg: cfg}
+}
+
+// Attempt reserves an attempt with key before its credentials are checked.
+// It returns a *LimitedError if the attempt must wait. Otherwise the attempt
+// already counts as failed, so concurrent attempts see it, until Release or
+// Reset takes it back. Lockable keys are locked once they reach MaxFailures
+// within the window; others are only slowed down. locked reports whether
+// this attempt caused the lock.
+func (t *Throttler) Attempt(key string, lockable bool) (locked bool, err error) {
+	now := time.Now()
+	var limited *LimitedError
+	_, err = t.store.Update(key, func(r *Record) {
+		if limited = t.limit(*r, now); limited != nil {
+			return
+		}
+		if r.Failures == 0 || now.Sub(r.FirstFailureAt) > t.cfg.Window {
+			*r = Record{FirstFailureAt: now}
+		}
+		r.Failures++
+		r.LastFailureAt = now
+		if lockable && r.Failures >= t.cfg.MaxFailures {
+			locked = r.LockedUntil == nil || !now.Before(*r.LockedUntil)
+			lockedUntil := now.Add(t.cfg.LockoutDuration)
+			r.LockedUntil = &lockedUntil
+		}
+	})
+	if err != nil {
+		return false, err
+	}
+	if limited != nil {
+		return false, limited
+	}
+	return locked, nil
+}
+
+// Release takes back an attempt reserved by Attempt that did not fail, for
+// example because the credentials could not be checked. locked is what
+// Attempt returned, so a lock caused by the attempt is lifted too.
+func (t *Throttler) Release(key string, locked bool) error {
+	_, err := t.store.Update(key, func(r *Record) {
+		if r.Failures > 0 {
+			r.Failures--
+		}
+		if locked {
+			r.LockedUntil = nil
+		}
+	})
+	return err
+}
+
+// Reset clears the failures and any lock of key, after a successful attempt
+// or when an admin unlocks an account.
+func (t *Throttler) Reset(key string) error {
+	return t.store.Delete(key)
+}
+
+// limit returns a *LimitedError if an attempt with the key of record must
+// wait at now.
+func (t *Throttler) limit(record Record, now time.Time) *LimitedError {
+	if record.LockedUntil != nil && now.Before(*record.LockedUntil) {
+		return &LimitedError{Locked: true, RetryAfter: record.LockedUntil.Sub(now)}
+	}
+	if record.Failures <= t.cfg.FreeFailures || now.Sub(record.FirstFailureAt) > t.cfg.Window {
+		return nil
+	}
+	if next := record.LastFailureAt.Add(t.delay(record.Failures)); now.Before(next) {
+		return &LimitedError{RetryAfter: next.Sub(now)}
+	}
+	return nil
+}
+
+func (t *Throttler) delay(failures int) time.Duration {
+	d := t.cfg.BaseDelay
+	for i := t.cfg.FreeFailures + 1; i < failures && d < t.cfg.MaxDelay; i++ {
+		d *= 2
+	}
+	return min(d, t.cfg.MaxDelay)
+}
@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Application statuses
+const (
+	ApplicationApplied   = "applied"
+	ApplicationWithdrawn = "withdrawn"
+)
+
+var (
+	// ErrAlreadyApplied is returned when applying to a drive twice.
+	ErrAlreadyApplied = errors.New("already applied to this drive")
+	// ErrAlreadyWithdrawn is returned when withdrawing an application twice.
+	ErrAlreadyWithdrawn = errors.New("application is already withdrawn")
+)
+
+// Application is a student's registration for a drive. Withdrawing keeps
+// the application, and applying again reuses it.
+type Application struct {
+	ApplicationID string
+	InstitutionID string
+	DriveID       string
+	StudentID     string
+	Status        string
+	AppliedAt     time.Time
+	WithdrawnAt   *time.Time
+	UpdatedAt     time.Time
+}
+
+func NewApplication(institutionID, driveID, studentID string) *Application {
+	now := time.Now()
+	return &Application{
+		ApplicationID: uuid.NewString(),
+		InstitutionID: institutionID,
+		DriveID:       driveID,
+		StudentID:     studentID,
+		Status:        ApplicationApplied,
+		AppliedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+// Reapply turns a withdrawn application into a fresh one.
+func (a *Application) Reapply() error {
+	if a.Status == ApplicationApplied {
+		return ErrAlreadyApplied
+	}
+	now := time.Now()
+	a.Status = ApplicationApplied
+	a.AppliedAt = now
+	a.WithdrawnAt = nil
+	a.UpdatedAt = now
+	return nil
+}
+
+func (a *Application) Withdraw() error {
+	if a.Status == ApplicationWithdrawn {
+		return ErrAlreadyWithdrawn
+	}
+	now := time.Now()
+	a.Status = ApplicationWithdrawn
+	a.WithdrawnAt = &now
+	a.UpdatedAt = now
+	return nil
+}
+
+// StudentApplication is an application as the student sees it, with a
+// summary of the drive.
+type StudentApplication struct {
+	Application
+	CompanyID   string
+	CompanyName string
+	DriveDate   time.Time
+	DriveStatus string
+}
+
+// Applicant is an application as officers see it, with the academic record
+// of the student.
+type Applicant struct {
+	Application
+	UserName   string
+	Email      string
+	RollNumber string
+	Branch     string
+	Batch      int
+	CGPA       float64
+	Backlogs   int
+}
+
+// ApplicantFilter selects a page of the applicants of a drive ordered by
+// roll number. After is the roll number of the last applicant of the
+// previous page.
+type ApplicantFilter struct {
+	InstitutionID string
+	DriveID       string
+	Status        string
+	After         string
+	Limit         int
+}
@@ -0,0 +1,203 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Round result statuses. Only shortlisted candidates move on to the next
+// round.
+const (
+	ResultShortlisted = "shortlisted"
+	ResultRejected    = "rejected"
+	ResultAbsent      = "absent"
+)
+
+// ResultStatuses lists every round result status
+var ResultStatuses = []string{ResultShortlisted, ResultRejected, ResultAbsent}
+
+const (
+	maxRounds    = 20
+	maxRoundName = 100
+	maxScore     = 9999.99
+)
+
+var (
+	// ErrInvalidRound is wrapped by every validation error of a round plan.
+	ErrInvalidRound = errors.New("invalid rounds")
+	// ErrInvalidResult is wrapped by every validation error of a result.
+	ErrInvalidResult = errors.New("invalid result")
+	// ErrRoundHasResults is returned when dropping or moving a round whose
+	// results have been recorded.
+	ErrRoundHasResults = errors.New("round already has results")
+)
+
+// Round is one stage of the selection process of a drive, for example an
+// aptitude test or an HR interview. Position orders the rounds from 1.
+type Round struct {
+	RoundID       string
+	InstitutionID string
+	DriveID       string
+	Position      int
+	Name          string
+	CreatedAt     time.Time
+}
+
+// PlanRounds orders the rounds of a drive by names. Rounds already in
+// existing keep their ID, and with it their results, when their name is
+// listed again; the others are dropped. Rounds in recorded, by ID, have
+// results, which are only meaningful relative to the rounds around them,
+// so they can neither be dropped nor change position.
+func PlanRounds(institutionID, driveID string, existing []*Round, recorded map[string]bool, names []string) ([]*Round, error) {
+	now := time.Now()
+	var planned []*Round
+	for _, name := range names {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			return nil, fmt.Errorf("%w: round names cannot be empty", ErrInvalidRound)
+		} else if len(name) > maxRoundName {
+			return nil, fmt.Errorf("%w: round name %q is longer than %d characters", ErrInvalidRound, name, maxRoundName)
+		}
+		if slices.ContainsFunc(planned, func(r *Round) bool { return strings.EqualFold(r.Name, name) }) {
+			return nil, fmt.Errorf("%w: round %q is listed twice", ErrInvalidRound, name)
+		}
+
+		round := &Round{
+			RoundID:       uuid.NewString(),
+			InstitutionID: institutionID,
+			DriveID:       driveID,
+			Name:          name,
+			CreatedAt:     now,
+		}
+		round.Position = len(planned) + 1
+		if i := slices.IndexFunc(existing, func(r *Round) bool { return strings.EqualFold(r.Name, name) }); i >= 0 {
+			if recorded[existing[i].RoundID] && existing[i].Position != round.Position {
+				return nil, fmt.Errorf("%w: %s cannot move from position %d", ErrRoundHasResults, existing[i].Name, existing[i].Position)
+			}
+			round.RoundID = existing[i].RoundID
+			round.CreatedAt = existing[i].CreatedAt
+		}
+		planned = append(planned, round)
+	}
+	if len(planned) > maxRounds {
+		return nil, fmt.Errorf("%w: at most %d rounds are allowed", ErrInvalidRound, maxRounds)
+	}
+
+	for _, round := range existing {
+		if recorded[round.RoundID] && !slices.ContainsFunc(planned, func(r *Round) bool { return r.RoundID == round.RoundID }) {
+			return nil, fmt.Errorf("%w: %s", ErrRoundHasResults, round.Name)
+		}
+	}
+	return planned, nil
+}
+
+// RoundResult is the outcome of one candidate in one round. Score is nil
+// when the round is not scored.
+type RoundResult struct {
+	RoundID       string
+	ApplicationID string
+	Status        string
+	Score         *float64
+	RecordedBy    string
+	RecordedAt    time.Time
+}
+
+func NewRoundResult(roundID, applicationID, status string, score *float64, recordedBy string) (*RoundResult, error) {
+	if !slices.Contains(ResultStatuses, status) {
+		return nil, fmt.Errorf("%w: status must be one of %s", ErrInvalidResult, strings.Join(ResultStatuses, ", "))
+	}
+	result := &RoundResult{
+		RoundID:       roundID,
+		ApplicationID: applicationID,
+		Status:        status,
+		RecordedBy:    recordedBy,
+		RecordedAt:    time.Now(),
+	}
+	if score != nil {
+		if *score < 0 || *score > maxScore {
+			return nil, fmt.Errorf("%w: score must be between 0 and %.2f", ErrInvalidResult, maxScore)
+		}
+		// The database keeps two decimals
+		rounded := math.Round(*score*100) / 100
+		result.Score = &rounded
+	}
+	return result, nil
+}
+
+// Survived reports whether the candidate moves on to the next round.
+func (r *RoundResult) Survived() bool {
+	return r.Status == ResultShortlisted
+}
+
+// ResultEntry is one line of a bulk result upload. Row numbers the entries
+// from 1 for error messages.
+type ResultEntry struct {
+	Row        int
+	RollNumber string
+	Status     string
+	Score      *float64
+}
+
+// CandidateResult is a round result with the candidate's identity.
+type CandidateResult struct {
+	RoundResult
+	UserName   string
+	RollNumber string
+}
+
+// RoundProgress counts the candidates of one round. Candidates are the
+// active applicants for the first round and those shortlisted in the
+// previous round afterwards; Pending ones have no result yet.
+type RoundProgress struct {
+	Round       *Round
+	Candidates  int
+	Shortlisted int
+	Rejected    int
+	Absent      int
+	Pending     int
+}
+
+// Progression follows the active applications of a drive through its
+// rounds, in order. results holds the results of the drive's rounds;
+// results of withdrawn applications are ignored.
+func Progression(rounds []*Round, applicationIDs []string, results []*RoundResult) []RoundProgress {
+	byRound := make(map[string]map[string]*RoundResult, len(rounds))
+	for _, result := range results {
+		if byRound[result.RoundID] == nil {
+			byRound[result.RoundID] = make(map[string]*RoundResult)
+		}
+		byRound[result.RoundID][result.ApplicationID] = result
+	}
+
+	candidates := applicationIDs
+	progression := make([]RoundProgress, 0, len(rounds))
+	for _, round := range rounds {
+		progress := RoundProgress{Round: round, Candidates: len(candidates)}
+		var survivors []string
+		for _, applicationID := range candidates {
+			result, ok := byRound[round.RoundID][applicationID]
+			if !ok {
+				progress.Pending++
+				continue
+			}
+			switch result.Status {
+			case ResultShortlisted:
+				progress.Shortlisted++
+				survivors = append(survivors, applicationID)
+			case ResultRejected:
+				progress.Rejected++
+			case ResultAbsent:
+				progress.Absent++
+			}
+		}
+		progression = append(progression, progress)
+		candidates = survivors
+	}
+	return progression
+}
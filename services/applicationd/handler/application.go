@@ -0,0 +1,254 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/applicationd/entity"
+	"backend/services/applicationd/presenter"
+	"backend/services/applicationd/usecase/application"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// errorStatus maps usecase errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, application.ErrApplicationNotFound), errors.Is(err, application.ErrDriveNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, application.ErrRegistrationClosed), errors.Is(err, application.ErrProfileMissing),
+		errors.Is(err, application.ErrProfileNotVerified), errors.Is(err, entity.ErrAlreadyApplied),
+		errors.Is(err, entity.ErrAlreadyWithdrawn):
+		return http.StatusConflict
+	case errors.As(err, new(*application.IneligibleError)):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, application.ErrInvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeIneligibleError reports the unmet eligibility criteria as JSON. It
+// returns false, writing nothing, for any other error.
+func writeIneligibleError(w http.ResponseWriter, err error) bool {
+	var ineligible *application.IneligibleError
+	if !errors.As(err, &ineligible) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusUnprocessableEntity)
+	if err := json.NewEncoder(w).Encode(presenter.IneligibleResponse{
+		Error:         ineligible.Error(),
+		UnmetCriteria: ineligible.Unmet,
+	}); err != nil {
+		log.Printf("unable to encode eligibility error, err=%v", err)
+	}
+	return true
+}
+
+func toApplicationResponse(a *entity.Application) presenter.ApplicationResponse {
+	resp := presenter.ApplicationResponse{
+		ApplicationID: a.ApplicationID,
+		DriveID:       a.DriveID,
+		StudentID:     a.StudentID,
+		Status:        a.Status,
+		AppliedAt:     a.AppliedAt.Unix(),
+		UpdatedAt:     a.UpdatedAt.Unix(),
+	}
+	if a.WithdrawnAt != nil {
+		resp.WithdrawnAt = a.WithdrawnAt.Unix()
+	}
+	return resp
+}
+
+func writeApplication(w http.ResponseWriter, a *entity.Application) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(toApplicationResponse(a)); err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func apply(service application.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.ApplyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+		if _, err := uuid.Parse(req.DriveID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid drive ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		applied, err := service.Apply(auth.FromContext(r.Context()), req.DriveID)
+		if err != nil {
+			if writeIneligibleError(w, err) {
+				return
+			}
+			http.Error(w, fmt.Sprintf("unable to apply, err=%v", err), errorStatus(err))
+			return
+		}
+		writeApplication(w, applied)
+	}
+}
+
+func withdraw(service application.Usecase, applicationID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		withdrawn, err := service.Withdraw(auth.FromContext(r.Context()), applicationID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to withdraw application, err=%v", err), errorStatus(err))
+			return
+		}
+		writeApplication(w, withdrawn)
+	}
+}
+
+func listOwnApplications(service application.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		applications, err := service.ListOwnApplications(auth.FromContext(r.Context()))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list applications, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.StudentApplicationResponse, 0, len(applications))
+		for _, a := range applications {
+			resp = append(resp, presenter.StudentApplicationResponse{
+				ApplicationResponse: toApplicationResponse(&a.Application),
+				CompanyID:           a.CompanyID,
+				CompanyName:         a.CompanyName,
+				DriveDate:           a.DriveDate.Unix(),
+				DriveStatus:         a.DriveStatus,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// listApplicants serves the applicants of a drive to officers:
+// GET /v1/applications?drive_id=&status=&cursor=&limit=
+func listApplicants(service application.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter, err := parseApplicantFilter(query)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to parse query, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		applicants, next, err := service.ListApplicants(auth.FromContext(r.Context()), filter, query.Get("cursor"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list applicants, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := presenter.ListApplicantsResponse{
+			Applicants: make([]presenter.ApplicantResponse, 0, len(applicants)),
+			NextCursor: next,
+		}
+		for _, a := range applicants {
+			resp.Applicants = append(resp.Applicants, presenter.ApplicantResponse{
+				ApplicationResponse: toApplicationResponse(&a.Application),
+				UserName:            a.UserName,
+				Email:               a.Email,
+				RollNumber:          a.RollNumber,
+				Branch:              a.Branch,
+				Batch:               a.Batch,
+				CGPA:                a.CGPA,
+				Backlogs:            a.Backlogs,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func parseApplicantFilter(query url.Values) (entity.ApplicantFilter, error) {
+	filter := entity.ApplicantFilter{
+		DriveID: query.Get("drive_id"),
+		Status:  query.Get("status"),
+	}
+
+	if _, err := uuid.Parse(filter.DriveID); err != nil {
+		return filter, fmt.Errorf("invalid drive ID format: %v", err)
+	}
+	switch filter.Status {
+	case "", entity.ApplicationApplied, entity.ApplicationWithdrawn:
+	default:
+		return filter, fmt.Errorf("unknown status %q", filter.Status)
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", v)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+// Register Application Routes
+func RegisterApplicationHandlers(service application.Usecase, authn *auth.Authenticator) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.Handle(pattern, authn.Middleware(handler))
+	}
+
+	handle("/v1/me/applications", listOwnApplications(service)) // GET
+	handle("/v1/applications", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listApplicants(service)(w, r)
+		case http.MethodPost:
+			apply(service)(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	handle("/v1/applications/", func(w http.ResponseWriter, r *http.Request) {
+		// Split /v1/applications/{id}/{action}
+		id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/applications/"), "/"), "/")
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, fmt.Sprintf("invalid application ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		switch action {
+		case "withdraw":
+			withdraw(service, id)(w, r) //v1/applications/{id}/withdraw // PUT
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+}
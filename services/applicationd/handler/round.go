@@ -0,0 +1,335 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/applicationd/entity"
+	"backend/services/applicationd/presenter"
+	"backend/services/applicationd/usecase/round"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// maxUploadBytes bounds the body of a bulk result upload
+const maxUploadBytes = 5 << 20
+
+// roundErrorStatus maps round usecase errors to HTTP status codes
+func roundErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, round.ErrRoundNotFound), errors.Is(err, round.ErrDriveNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrRoundHasResults):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrInvalidRound), errors.Is(err, round.ErrInvalidUpload),
+		errors.As(err, new(*round.UploadError)):
+		return http.StatusBadRequest
+	default:
+		return errorStatus(err)
+	}
+}
+
+// writeUploadError reports the problems of a bulk upload as JSON. It
+// returns false, writing nothing, for any other error.
+func writeUploadError(w http.ResponseWriter, err error) bool {
+	var invalid *round.UploadError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(presenter.UploadErrorResponse{
+		Error:    invalid.Error(),
+		Problems: invalid.Problems,
+	}); err != nil {
+		log.Printf("unable to encode upload error, err=%v", err)
+	}
+	return true
+}
+
+func toRoundResponse(r *entity.Round) presenter.RoundResponse {
+	return presenter.RoundResponse{
+		RoundID:   r.RoundID,
+		DriveID:   r.DriveID,
+		Position:  r.Position,
+		Name:      r.Name,
+		CreatedAt: r.CreatedAt.Unix(),
+	}
+}
+
+func writeRounds(w http.ResponseWriter, rounds []*entity.Round) {
+	resp := make([]presenter.RoundResponse, 0, len(rounds))
+	for _, r := range rounds {
+		resp = append(resp, toRoundResponse(r))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func parseDriveID(r *http.Request) (string, error) {
+	driveID := r.URL.Query().Get("drive_id")
+	if _, err := uuid.Parse(driveID); err != nil {
+		return "", fmt.Errorf("invalid drive ID format: %v", err)
+	}
+	return driveID, nil
+}
+
+// listRounds serves GET /v1/rounds?drive_id=
+func listRounds(service round.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		driveID, err := parseDriveID(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		rounds, err := service.ListRounds(auth.FromContext(r.Context()), driveID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list rounds, err=%v", err), roundErrorStatus(err))
+			return
+		}
+		writeRounds(w, rounds)
+	}
+}
+
+func setRounds(service round.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.RoundsRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+		if _, err := uuid.Parse(req.DriveID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid drive ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		rounds, err := service.SetRounds(auth.FromContext(r.Context()), req.DriveID, req.Rounds)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to set rounds, err=%v", err), roundErrorStatus(err))
+			return
+		}
+		writeRounds(w, rounds)
+	}
+}
+
+// getProgression serves GET /v1/rounds/progression?drive_id=
+func getProgression(service round.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		driveID, err := parseDriveID(r)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		applicants, progression, err := service.GetProgression(auth.FromContext(r.Context()), driveID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to get progression, err=%v", err), roundErrorStatus(err))
+			return
+		}
+
+		resp := presenter.ProgressionResponse{
+			DriveID:    driveID,
+			Applicants: applicants,
+			Rounds:     make([]presenter.RoundProgressResponse, 0, len(progression)),
+		}
+		for _, p := range progression {
+			resp.Rounds = append(resp.Rounds, presenter.RoundProgressResponse{
+				RoundResponse: toRoundResponse(p.Round),
+				Candidates:    p.Candidates,
+				Shortlisted:   p.Shortlisted,
+				Rejected:      p.Rejected,
+				Absent:        p.Absent,
+				Pending:       p.Pending,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func listRoundResults(service round.Usecase, roundID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		results, err := service.ListRoundResults(auth.FromContext(r.Context()), roundID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list results, err=%v", err), roundErrorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.ResultResponse, 0, len(results))
+		for _, result := range results {
+			resp = append(resp, presenter.ResultResponse{
+				ApplicationID: result.ApplicationID,
+				RollNumber:    result.RollNumber,
+				UserName:      result.UserName,
+				Status:        result.Status,
+				Score:         result.Score,
+				RecordedBy:    result.RecordedBy,
+				RecordedAt:    result.RecordedAt.Unix(),
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// recordResults takes the results of a round either as JSON or as a CSV
+// spreadsheet export with roll_number, status and score columns.
+func recordResults(service round.Usecase, roundID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body := http.MaxBytesReader(w, r.Body, maxUploadBytes)
+
+		var entries []entity.ResultEntry
+		var err error
+		if mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type")); mediaType == "text/csv" {
+			entries, err = parseResultsCSV(body)
+		} else {
+			var req presenter.ResultsRequest
+			err = json.NewDecoder(body).Decode(&req)
+			for i, result := range req.Results {
+				entries = append(entries, entity.ResultEntry{
+					Row:        i + 1,
+					RollNumber: result.RollNumber,
+					Status:     result.Status,
+					Score:      result.Score,
+				})
+			}
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		recorded, err := service.RecordResults(auth.FromContext(r.Context()), roundID, entries)
+		if err != nil {
+			if writeUploadError(w, err) {
+				return
+			}
+			http.Error(w, fmt.Sprintf("unable to record results, err=%v", err), roundErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(presenter.ResultsRecordedResponse{Recorded: recorded}); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// parseResultsCSV reads results from CSV with a header row. Rows are
+// numbered from the first line after the header; an empty score means the
+// round is not scored.
+func parseResultsCSV(body io.Reader) ([]entity.ResultEntry, error) {
+	reader := csv.NewReader(body)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("missing header row: %w", err)
+	}
+	columns := map[string]int{"roll_number": -1, "status": -1, "score": -1}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if _, ok := columns[name]; ok {
+			columns[name] = i
+		}
+	}
+	if columns["roll_number"] < 0 || columns["status"] < 0 {
+		return nil, errors.New("header must have roll_number and status columns")
+	}
+	field := func(record []string, name string) string {
+		if i := columns[name]; i >= 0 && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+		return ""
+	}
+
+	var entries []entity.ResultEntry
+	for row := 1; ; row++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return entries, nil
+		} else if err != nil {
+			return nil, err
+		}
+
+		entry := entity.ResultEntry{
+			Row:        row,
+			RollNumber: field(record, "roll_number"),
+			Status:     strings.ToLower(field(record, "status")),
+		}
+		if v := field(record, "score"); v != "" {
+			score, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("row %d: invalid score %q", row, v)
+			}
+			entry.Score = &score
+		}
+		entries = append(entries, entry)
+	}
+}
+
+// Register Round Routes
+func RegisterRoundHandlers(service round.Usecase, authn *auth.Authenticator) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.Handle(pattern, authn.Middleware(handler))
+	}
+
+	handle("/v1/rounds", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listRounds(service)(w, r)
+		case http.MethodPut:
+			setRounds(service)(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	handle("/v1/rounds/progression", getProgression(service)) // GET ?drive_id=
+	handle("/v1/rounds/", func(w http.ResponseWriter, r *http.Request) {
+		// Split /v1/rounds/{id}/{action}
+		id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/rounds/"), "/"), "/")
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, fmt.Sprintf("invalid round ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case action == "results" && r.Method == http.MethodGet:
+			listRoundResults(service, id)(w, r) //v1/rounds/{id}/results // GET
+		case action == "results" && r.Method == http.MethodPost:
+			recordResults(service, id)(w, r) //v1/rounds/{id}/results // POST JSON or CSV
+		case action == "results":
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+}
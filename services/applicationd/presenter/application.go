@@ -0,0 +1,47 @@
+package presenter
+
+type ApplyRequest struct {
+	DriveID string `json:"drive_id"`
+}
+
+type ApplicationResponse struct {
+	ApplicationID string `json:"application_id"`
+	DriveID       string `json:"drive_id"`
+	StudentID     string `json:"student_id"`
+	Status        string `json:"status"`
+	AppliedAt     int64  `json:"applied_at"`
+	WithdrawnAt   int64  `json:"withdrawn_at,omitempty"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+// StudentApplicationResponse is an application with a summary of its drive.
+type StudentApplicationResponse struct {
+	ApplicationResponse
+	CompanyID   string `json:"company_id"`
+	CompanyName string `json:"company_name"`
+	DriveDate   int64  `json:"drive_date"`
+	DriveStatus string `json:"drive_status"`
+}
+
+// ApplicantResponse is an application with the student's academic record.
+type ApplicantResponse struct {
+	ApplicationResponse
+	UserName   string  `json:"user_name"`
+	Email      string  `json:"email"`
+	RollNumber string  `json:"roll_number"`
+	Branch     string  `json:"branch"`
+	Batch      int     `json:"batch"`
+	CGPA       float64 `json:"cgpa"`
+	Backlogs   int     `json:"backlogs"`
+}
+
+type ListApplicantsResponse struct {
+	Applicants []ApplicantResponse `json:"applicants"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// IneligibleResponse lists the eligibility criteria the student does not meet.
+type IneligibleResponse struct {
+	Error         string   `json:"error"`
+	UnmetCriteria []string `json:"unmet_criteria"`
+}
@@ -0,0 +1,62 @@
+package presenter
+
+// RoundsRequest lists the round names of a drive in order.
+type RoundsRequest struct {
+	DriveID string   `json:"drive_id"`
+	Rounds  []string `json:"rounds"`
+}
+
+type RoundResponse struct {
+	RoundID   string `json:"round_id"`
+	DriveID   string `json:"drive_id"`
+	Position  int    `json:"position"`
+	Name      string `json:"name"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+// ResultRequest is one row of a bulk result upload. A null score means the
+// round is not scored.
+type ResultRequest struct {
+	RollNumber string   `json:"roll_number"`
+	Status     string   `json:"status"`
+	Score      *float64 `json:"score"`
+}
+
+type ResultsRequest struct {
+	Results []ResultRequest `json:"results"`
+}
+
+type ResultsRecordedResponse struct {
+	Recorded int `json:"recorded"`
+}
+
+type ResultResponse struct {
+	ApplicationID string   `json:"application_id"`
+	RollNumber    string   `json:"roll_number"`
+	UserName      string   `json:"user_name"`
+	Status        string   `json:"status"`
+	Score         *float64 `json:"score"`
+	RecordedBy    string   `json:"recorded_by"`
+	RecordedAt    int64    `json:"recorded_at"`
+}
+
+// UploadErrorResponse lists the problems of the rows of a bulk upload.
+type UploadErrorResponse struct {
+	Error    string   `json:"error"`
+	Problems []string `json:"problems"`
+}
+
+type RoundProgressResponse struct {
+	RoundResponse
+	Candidates  int `json:"candidates"`
+	Shortlisted int `json:"shortlisted"`
+	Rejected    int `json:"rejected"`
+	Absent      int `json:"absent"`
+	Pending     int `json:"pending"`
+}
+
+type ProgressionResponse struct {
+	DriveID    string                  `json:"drive_id"`
+	Applicants int                     `json:"applicants"`
+	Rounds     []RoundProgressResponse `json:"rounds"`
+}
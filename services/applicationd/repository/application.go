@@ -0,0 +1,198 @@
+package repository
+
+import (
+	"backend/services/applicationd/entity"
+	"database/sql"
+	"errors"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// errDuplicateKey is the MySQL error number of unique key violations.
+const errDuplicateKey = 1062
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewApplicationRepository(db *sql.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+const applicationColumns = `
+	a.application_id, a.institution_id, a.drive_id, a.student_id, a.status, a.applied_at, a.withdrawn_at, a.updated_at`
+
+// CreateApplication returns entity.ErrAlreadyApplied when the student has
+// applied to the drive in the meantime.
+func (r *Repository) CreateApplication(application *entity.Application) error {
+	_, err := r.db.Exec(`
+		INSERT INTO applications
+		(application_id, institution_id, drive_id, student_id, status, applied_at, withdrawn_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`, application.ApplicationID, application.InstitutionID, application.DriveID, application.StudentID,
+		application.Status, application.AppliedAt, application.WithdrawnAt, application.UpdatedAt)
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == errDuplicateKey {
+		return entity.ErrAlreadyApplied
+	}
+	return err
+}
+
+func (r *Repository) UpdateApplication(application *entity.Application) error {
+	result, err := r.db.Exec(`
+		UPDATE applications SET status = ?, applied_at = ?, withdrawn_at = ?, updated_at = ?
+		WHERE application_id = ? AND institution_id = ?;
+	`, application.Status, application.AppliedAt, application.WithdrawnAt, application.UpdatedAt,
+		application.ApplicationID, application.InstitutionID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *Repository) GetApplication(institutionID, applicationID string) (*entity.Application, error) {
+	return scanApplication(r.db.QueryRow(`
+		SELECT `+applicationColumns+`
+		FROM applications a WHERE a.institution_id = ? AND a.application_id = ?;
+	`, institutionID, applicationID))
+}
+
+// GetStudentApplication returns the application of a student to a drive.
+func (r *Repository) GetStudentApplication(institutionID, driveID, studentID string) (*entity.Application, error) {
+	return scanApplication(r.db.QueryRow(`
+		SELECT `+applicationColumns+`
+		FROM applications a WHERE a.institution_id = ? AND a.drive_id = ? AND a.student_id = ?;
+	`, institutionID, driveID, studentID))
+}
+
+// ListStudentApplications returns every application of a student, latest
+// drive first.
+func (r *Repository) ListStudentApplications(institutionID, studentID string) ([]*entity.StudentApplication, error) {
+	rows, err := r.db.Query(`
+		SELECT `+applicationColumns+`, d.company_id, c.company_name, d.drive_date, d.status
+		FROM applications a
+		JOIN drives d ON d.drive_id = a.drive_id
+		JOIN company_data c ON c.id = d.company_id
+		WHERE a.institution_id = ? AND a.student_id = ?
+		ORDER BY d.drive_date DESC, a.application_id;
+	`, institutionID, studentID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applications []*entity.StudentApplication
+	for rows.Next() {
+		var application entity.StudentApplication
+		var withdrawnAt sql.NullTime
+		if err := rows.Scan(&application.ApplicationID,
+			&application.InstitutionID,
+			&application.DriveID,
+			&application.StudentID,
+			&application.Status,
+			&application.AppliedAt,
+			&withdrawnAt,
+			&application.UpdatedAt,
+			&application.CompanyID,
+			&application.CompanyName,
+			&application.DriveDate,
+			&application.DriveStatus); err != nil {
+			return nil, err
+		}
+		if withdrawnAt.Valid {
+			application.WithdrawnAt = &withdrawnAt.Time
+		}
+		applications = append(applications, &application)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// ListApplicants returns the applicants matching filter, by roll number.
+func (r *Repository) ListApplicants(filter entity.ApplicantFilter) ([]*entity.Applicant, error) {
+	query := `
+		SELECT ` + applicationColumns + `, u.user_name, u.email, s.roll_number, s.branch, s.batch, s.cgpa, s.backlogs
+		FROM applications a
+		JOIN students s ON s.user_id = a.student_id
+		JOIN users u ON u.user_id = a.student_id
+		WHERE a.institution_id = ? AND a.drive_id = ?`
+	args := []any{filter.InstitutionID, filter.DriveID}
+
+	if filter.Status != "" {
+		query += " AND a.status = ?"
+		args = append(args, filter.Status)
+	}
+	if filter.After != "" {
+		query += " AND s.roll_number > ?"
+		args = append(args, filter.After)
+	}
+	query += " ORDER BY s.roll_number LIMIT ?;"
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applicants []*entity.Applicant
+	for rows.Next() {
+		var applicant entity.Applicant
+		var withdrawnAt sql.NullTime
+		if err := rows.Scan(&applicant.ApplicationID,
+			&applicant.InstitutionID,
+			&applicant.DriveID,
+			&applicant.StudentID,
+			&applicant.Status,
+			&applicant.AppliedAt,
+			&withdrawnAt,
+			&applicant.UpdatedAt,
+			&applicant.UserName,
+			&applicant.Email,
+			&applicant.RollNumber,
+			&applicant.Branch,
+			&applicant.Batch,
+			&applicant.CGPA,
+			&applicant.Backlogs); err != nil {
+			return nil, err
+		}
+		if withdrawnAt.Valid {
+			applicant.WithdrawnAt = &withdrawnAt.Time
+		}
+		applicants = append(applicants, &applicant)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return applicants, nil
+}
+
+func scanApplication(row *sql.Row) (*entity.Application, error) {
+	var application entity.Application
+	var withdrawnAt sql.NullTime
+	if err := row.Scan(&application.ApplicationID,
+		&application.InstitutionID,
+		&application.DriveID,
+		&application.StudentID,
+		&application.Status,
+		&application.AppliedAt,
+		&withdrawnAt,
+		&application.UpdatedAt); err != nil {
+		return nil, err
+	}
+	if withdrawnAt.Valid {
+		application.WithdrawnAt = &withdrawnAt.Time
+	}
+	return &application, nil
+}
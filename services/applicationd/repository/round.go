@@ -0,0 +1,310 @@
+package repository
+
+import (
+	"backend/services/applicationd/entity"
+	"database/sql"
+	"strings"
+)
+
+// querier runs queries on the database or inside a transaction.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+// UpdateRounds changes the rounds of a drive in one transaction. plan gets
+// the current rounds and the IDs of those with results, both locked until
+// the transaction ends, and returns the new rounds in order. Rounds missing
+// from them are deleted along with their results.
+func (r *Repository) UpdateRounds(institutionID, driveID string, plan func(existing []*entity.Round, recorded map[string]bool) ([]*entity.Round, error)) ([]*entity.Round, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	if err := lockDriveRounds(tx, institutionID, driveID); err != nil {
+		return nil, err
+	}
+	existing, err := listRounds(tx, institutionID, driveID)
+	if err != nil {
+		return nil, err
+	}
+	results, err := listDriveResults(tx, institutionID, driveID)
+	if err != nil {
+		return nil, err
+	}
+	recorded := make(map[string]bool)
+	for _, result := range results {
+		recorded[result.RoundID] = true
+	}
+
+	rounds, err := plan(existing, recorded)
+	if err != nil {
+		return nil, err
+	}
+
+	query := "DELETE FROM drive_rounds WHERE institution_id = ? AND drive_id = ?"
+	args := []any{institutionID, driveID}
+	if len(rounds) > 0 {
+		query += " AND round_id NOT IN (?" + strings.Repeat(", ?", len(rounds)-1) + ")"
+		for _, round := range rounds {
+			args = append(args, round.RoundID)
+		}
+	}
+	if _, err := tx.Exec(query, args...); err != nil {
+		return nil, err
+	}
+
+	for _, round := range rounds {
+		_, err := tx.Exec(`
+			INSERT INTO drive_rounds (round_id, institution_id, drive_id, position, name, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE position = VALUES(position), name = VALUES(name);
+		`, round.RoundID, round.InstitutionID, round.DriveID, round.Position, round.Name, round.CreatedAt)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// lockDriveRounds locks the drive row for the rest of tx. Every change to
+// the rounds of a drive or their results takes this lock first, so checks
+// of the rounds and results made in tx stay true until it ends.
+func lockDriveRounds(tx *sql.Tx, institutionID, driveID string) error {
+	var id string
+	return tx.QueryRow("SELECT drive_id FROM drives WHERE drive_id = ? AND institution_id = ? FOR UPDATE;", driveID, institutionID).Scan(&id)
+}
+
+func (r *Repository) GetRound(institutionID, roundID string) (*entity.Round, error) {
+	var round entity.Round
+	err := r.db.QueryRow(`
+		SELECT round_id, institution_id, drive_id, position, name, created_at
+		FROM drive_rounds WHERE institution_id = ? AND round_id = ?;
+	`, institutionID, roundID).Scan(&round.RoundID, &round.InstitutionID, &round.DriveID, &round.Position, &round.Name, &round.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &round, nil
+}
+
+// ListRounds returns the rounds of a drive in order.
+func (r *Repository) ListRounds(institutionID, driveID string) ([]*entity.Round, error) {
+	return listRounds(r.db, institutionID, driveID)
+}
+
+func listRounds(q querier, institutionID, driveID string) ([]*entity.Round, error) {
+	rows, err := q.Query(`
+		SELECT round_id, institution_id, drive_id, position, name, created_at
+		FROM drive_rounds WHERE institution_id = ? AND drive_id = ?
+		ORDER BY position;
+	`, institutionID, driveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rounds []*entity.Round
+	for rows.Next() {
+		var round entity.Round
+		if err := rows.Scan(&round.RoundID, &round.InstitutionID, &round.DriveID, &round.Position, &round.Name, &round.CreatedAt); err != nil {
+			return nil, err
+		}
+		rounds = append(rounds, &round)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// GetActiveApplications returns the applied, not withdrawn, applications
+// of a drive keyed by the roll number of their student.
+func (r *Repository) GetActiveApplications(institutionID, driveID string) (map[string]*entity.Application, error) {
+	return getActiveApplications(r.db, institutionID, driveID)
+}
+
+func getActiveApplications(q querier, institutionID, driveID string) (map[string]*entity.Application, error) {
+	rows, err := q.Query(`
+		SELECT `+applicationColumns+`, s.roll_number
+		FROM applications a
+		JOIN students s ON s.user_id = a.student_id
+		WHERE a.institution_id = ? AND a.drive_id = ? AND a.status = ?;
+	`, institutionID, driveID, entity.ApplicationApplied)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applications := make(map[string]*entity.Application)
+	for rows.Next() {
+		var application entity.Application
+		var withdrawnAt sql.NullTime
+		var rollNumber string
+		if err := rows.Scan(&application.ApplicationID,
+			&application.InstitutionID,
+			&application.DriveID,
+			&application.StudentID,
+			&application.Status,
+			&application.AppliedAt,
+			&withdrawnAt,
+			&application.UpdatedAt,
+			&rollNumber); err != nil {
+			return nil, err
+		}
+		if withdrawnAt.Valid {
+			application.WithdrawnAt = &withdrawnAt.Time
+		}
+		applications[rollNumber] = &application
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return applications, nil
+}
+
+// RecordResults saves results of the rounds of a drive in one transaction,
+// replacing earlier results of the same candidates in the same rounds.
+// build gets the rounds, active applications and results of the drive,
+// locked until the transaction ends, and returns the results to save. It
+// returns how many were saved.
+func (r *Repository) RecordResults(institutionID, driveID string, build func(rounds []*entity.Round, applications map[string]*entity.Application, results []*entity.RoundResult) ([]*entity.RoundResult, error)) (int, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	if err := lockDriveRounds(tx, institutionID, driveID); err != nil {
+		return 0, err
+	}
+	rounds, err := listRounds(tx, institutionID, driveID)
+	if err != nil {
+		return 0, err
+	}
+	applications, err := getActiveApplications(tx, institutionID, driveID)
+	if err != nil {
+		return 0, err
+	}
+	existing, err := listDriveResults(tx, institutionID, driveID)
+	if err != nil {
+		return 0, err
+	}
+
+	results, err := build(rounds, applications, existing)
+	if err != nil {
+		return 0, err
+	}
+	for _, result := range results {
+		_, err := tx.Exec(`
+			INSERT INTO round_results (round_id, application_id, status, score, recorded_by, recorded_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON DUPLICATE KEY UPDATE status = VALUES(status), score = VALUES(score),
+				recorded_by = VALUES(recorded_by), recorded_at = VALUES(recorded_at);
+		`, result.RoundID, result.ApplicationID, result.Status, result.Score, result.RecordedBy, result.RecordedAt)
+		if err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(results), nil
+}
+
+// ListDriveResults returns the results of every round of a drive.
+func (r *Repository) ListDriveResults(institutionID, driveID string) ([]*entity.RoundResult, error) {
+	return listDriveResults(r.db, institutionID, driveID)
+}
+
+func listDriveResults(q querier, institutionID, driveID string) ([]*entity.RoundResult, error) {
+	rows, err := q.Query(`
+		SELECT rr.round_id, rr.application_id, rr.status, rr.score, rr.recorded_by, rr.recorded_at
+		FROM round_results rr
+		JOIN drive_rounds dr ON dr.round_id = rr.round_id
+		WHERE dr.institution_id = ? AND dr.drive_id = ?;
+	`, institutionID, driveID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*entity.RoundResult
+	for rows.Next() {
+		result, err := scanRoundResult(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// ListRoundResults returns the results of a round by roll number.
+func (r *Repository) ListRoundResults(institutionID, roundID string) ([]*entity.CandidateResult, error) {
+	rows, err := r.db.Query(`
+		SELECT rr.round_id, rr.application_id, rr.status, rr.score, rr.recorded_by, rr.recorded_at, u.user_name, s.roll_number
+		FROM round_results rr
+		JOIN drive_rounds dr ON dr.round_id = rr.round_id
+		JOIN applications a ON a.application_id = rr.application_id
+		JOIN students s ON s.user_id = a.student_id
+		JOIN users u ON u.user_id = a.student_id
+		WHERE dr.institution_id = ? AND rr.round_id = ?
+		ORDER BY s.roll_number;
+	`, institutionID, roundID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var results []*entity.CandidateResult
+	for rows.Next() {
+		var result entity.CandidateResult
+		var score sql.NullFloat64
+		if err := rows.Scan(&result.RoundID,
+			&result.ApplicationID,
+			&result.Status,
+			&score,
+			&result.RecordedBy,
+			&result.RecordedAt,
+			&result.UserName,
+			&result.RollNumber); err != nil {
+			return nil, err
+		}
+		if score.Valid {
+			result.Score = &score.Float64
+		}
+		results = append(results, &result)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanRoundResult(row scanner) (*entity.RoundResult, error) {
+	var result entity.RoundResult
+	var score sql.NullFloat64
+	if err := row.Scan(&result.RoundID,
+		&result.ApplicationID,
+		&result.Status,
+		&score,
+		&result.RecordedBy,
+		&result.RecordedAt); err != nil {
+		return nil, err
+	}
+	if score.Valid {
+		result.Score = &score.Float64
+	}
+	return &result, nil
+}
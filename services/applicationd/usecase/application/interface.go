@@ -0,0 +1,42 @@
+package application
+
+import (
+	"backend/pkg/auth"
+	"backend/services/applicationd/entity"
+	driveEntity "backend/services/datad/entity"
+	studentEntity "backend/services/studentd/entity"
+)
+
+type Repository interface {
+	Writer
+	Reader
+}
+
+type Writer interface {
+	CreateApplication(application *entity.Application) error
+	UpdateApplication(application *entity.Application) error
+}
+
+type Reader interface {
+	GetApplication(institutionID, applicationID string) (*entity.Application, error)
+	GetStudentApplication(institutionID, driveID, studentID string) (*entity.Application, error)
+	ListStudentApplications(institutionID, studentID string) ([]*entity.StudentApplication, error)
+	ListApplicants(filter entity.ApplicantFilter) ([]*entity.Applicant, error)
+}
+
+// DriveReader reads the drives students apply to.
+type DriveReader interface {
+	GetDrive(institutionID, driveID string) (*driveEntity.Drive, error)
+}
+
+// StudentReader reads the profiles eligibility is checked against.
+type StudentReader interface {
+	GetStudent(institutionID, userID string) (*studentEntity.Student, error)
+}
+
+type Usecase interface {
+	Apply(principal *auth.Principal, driveID string) (*entity.Application, error)
+	Withdraw(principal *auth.Principal, applicationID string) (*entity.Application, error)
+	ListOwnApplications(principal *auth.Principal) ([]*entity.StudentApplication, error)
+	ListApplicants(principal *auth.Principal, filter entity.ApplicantFilter, cursor string) ([]*entity.Applicant, string, error)
+}
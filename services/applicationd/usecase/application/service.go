@@ -0,0 +1,203 @@
+package application
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/applicationd/entity"
+	dataRepository "backend/services/datad/repository"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+var (
+	ErrApplicationNotFound = errors.New("application not found")
+	ErrDriveNotFound       = errors.New("drive not found")
+	ErrRegistrationClosed  = errors.New("registration for this drive is closed")
+	// ErrProfileMissing and ErrProfileNotVerified are returned to students
+	// whose academic record cannot be trusted for eligibility yet.
+	ErrProfileMissing     = errors.New("complete your student profile before applying")
+	ErrProfileNotVerified = errors.New("your student profile has not been verified yet")
+	ErrInvalidCursor      = errors.New("invalid cursor")
+)
+
+// IneligibleError lists the eligibility criteria of a drive a student does
+// not meet.
+type IneligibleError struct {
+	Unmet []string
+}
+
+func (e *IneligibleError) Error() string {
+	return "not eligible for this drive: " + strings.Join(e.Unmet, "; ")
+}
+
+type Service struct {
+	repo     Repository
+	drives   DriveReader
+	students StudentReader
+	authz    *auth.Authorizer
+}
+
+func NewService(repo Repository, drives DriveReader, students StudentReader, authz *auth.Authorizer) *Service {
+	return &Service{
+		repo:     repo,
+		drives:   drives,
+		students: students,
+		authz:    authz,
+	}
+}
+
+// Apply registers the caller for a drive after checking their verified
+// profile against the drive's eligibility rules. Applying again after a
+// withdrawal reopens the earlier application.
+func (s *Service) Apply(principal *auth.Principal, driveID string) (*entity.Application, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationApply); err != nil {
+		return nil, err
+	}
+
+	drive, err := s.drives.GetDrive(principal.InstitutionID, driveID)
+	if errors.Is(err, dataRepository.ErrNotFound) {
+		return nil, ErrDriveNotFound
+	} else if err != nil {
+		log.Printf("unable to get drive %s, err=%v", driveID, err)
+		return nil, err
+	}
+	if !drive.IsRegistrationOpen(time.Now()) {
+		return nil, ErrRegistrationClosed
+	}
+
+	student, err := s.students.GetStudent(principal.InstitutionID, principal.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrProfileMissing
+	} else if err != nil {
+		log.Printf("unable to get student %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	if !student.IsVerified() {
+		return nil, ErrProfileNotVerified
+	}
+	if unmet := drive.Eligibility.Unmet(student.CGPA, student.Branch, student.Backlogs, student.Batch); len(unmet) > 0 {
+		return nil, &IneligibleError{Unmet: unmet}
+	}
+
+	application, err := s.repo.GetStudentApplication(principal.InstitutionID, driveID, principal.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		application = entity.NewApplication(principal.InstitutionID, driveID, principal.UserID)
+		if err := s.repo.CreateApplication(application); err != nil {
+			log.Printf("unable to create application in repository, err=%v", err)
+			return nil, err
+		}
+		return application, nil
+	} else if err != nil {
+		log.Printf("unable to get application of student %s to drive %s, err=%v", principal.UserID, driveID, err)
+		return nil, err
+	}
+
+	if err := application.Reapply(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateApplication(application); err != nil {
+		log.Printf("unable to update application %s in repository, err=%v", application.ApplicationID, err)
+		return nil, err
+	}
+	return application, nil
+}
+
+// Withdraw cancels one of the caller's applications. Students can change
+// their mind until registration closes.
+func (s *Service) Withdraw(principal *auth.Principal, applicationID string) (*entity.Application, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationApply); err != nil {
+		return nil, err
+	}
+
+	application, err := s.repo.GetApplication(principal.InstitutionID, applicationID)
+	if errors.Is(err, sql.ErrNoRows) || (err == nil && application.StudentID != principal.UserID) {
+		return nil, ErrApplicationNotFound
+	} else if err != nil {
+		log.Printf("unable to get application %s, err=%v", applicationID, err)
+		return nil, err
+	}
+
+	drive, err := s.drives.GetDrive(principal.InstitutionID, application.DriveID)
+	if err != nil {
+		log.Printf("unable to get drive %s, err=%v", application.DriveID, err)
+		return nil, err
+	}
+	if !drive.IsRegistrationOpen(time.Now()) {
+		return nil, ErrRegistrationClosed
+	}
+
+	if err := application.Withdraw(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateApplication(application); err != nil {
+		log.Printf("unable to update application %s in repository, err=%v", applicationID, err)
+		return nil, err
+	}
+	return application, nil
+}
+
+func (s *Service) ListOwnApplications(principal *auth.Principal) ([]*entity.StudentApplication, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationApply); err != nil {
+		return nil, err
+	}
+
+	applications, err := s.repo.ListStudentApplications(principal.InstitutionID, principal.UserID)
+	if err != nil {
+		log.Printf("unable to list applications of student %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	return applications, nil
+}
+
+// ListApplicants returns one page of the applicants of a drive and the
+// cursor of the next page, which is empty on the last page.
+func (s *Service) ListApplicants(principal *auth.Principal, filter entity.ApplicantFilter, cursor string) ([]*entity.Applicant, string, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationRead); err != nil {
+		return nil, "", err
+	}
+	filter.InstitutionID = principal.InstitutionID
+
+	if _, err := s.drives.GetDrive(principal.InstitutionID, filter.DriveID); errors.Is(err, dataRepository.ErrNotFound) {
+		return nil, "", ErrDriveNotFound
+	} else if err != nil {
+		log.Printf("unable to get drive %s, err=%v", filter.DriveID, err)
+		return nil, "", err
+	}
+
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			return nil, "", ErrInvalidCursor
+		}
+		filter.After = string(after)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	} else if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to know whether there is a next page
+	filter.Limit++
+	applicants, err := s.repo.ListApplicants(filter)
+	if err != nil {
+		log.Printf("unable to list applicants of drive %s, err=%v", filter.DriveID, err)
+		return nil, "", err
+	}
+
+	if len(applicants) <= pageSize {
+		return applicants, "", nil
+	}
+	applicants = applicants[:pageSize]
+	return applicants, base64.RawURLEncoding.EncodeToString([]byte(applicants[len(applicants)-1].RollNumber)), nil
+}
@@ -0,0 +1,38 @@
+package round
+
+import (
+	"backend/pkg/auth"
+	"backend/services/applicationd/entity"
+	driveEntity "backend/services/datad/entity"
+)
+
+type Repository interface {
+	Writer
+	Reader
+}
+
+type Writer interface {
+	UpdateRounds(institutionID, driveID string, plan func(existing []*entity.Round, recorded map[string]bool) ([]*entity.Round, error)) ([]*entity.Round, error)
+	RecordResults(institutionID, driveID string, build func(rounds []*entity.Round, applications map[string]*entity.Application, results []*entity.RoundResult) ([]*entity.RoundResult, error)) (int, error)
+}
+
+type Reader interface {
+	GetRound(institutionID, roundID string) (*entity.Round, error)
+	ListRounds(institutionID, driveID string) ([]*entity.Round, error)
+	GetActiveApplications(institutionID, driveID string) (map[string]*entity.Application, error)
+	ListDriveResults(institutionID, driveID string) ([]*entity.RoundResult, error)
+	ListRoundResults(institutionID, roundID string) ([]*entity.CandidateResult, error)
+}
+
+// DriveReader reads the drives rounds belong to.
+type DriveReader interface {
+	GetDrive(institutionID, driveID string) (*driveEntity.Drive, error)
+}
+
+type Usecase interface {
+	ListRounds(principal *auth.Principal, driveID string) ([]*entity.Round, error)
+	SetRounds(principal *auth.Principal, driveID string, names []string) ([]*entity.Round, error)
+	RecordResults(principal *auth.Principal, roundID string, entries []entity.ResultEntry) (int, error)
+	ListRoundResults(principal *auth.Principal, roundID string) ([]*entity.CandidateResult, error)
+	GetProgression(principal *auth.Principal, driveID string) (int, []entity.RoundProgress, error)
+}
@@ -0,0 +1,264 @@
+package round
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/applicationd/entity"
+	dataRepository "backend/services/datad/repository"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// maxUploadRows bounds one bulk upload, which is saved in one transaction
+const maxUploadRows = 5000
+
+var (
+	ErrRoundNotFound = errors.New("round not found")
+	ErrDriveNotFound = errors.New("drive not found")
+	ErrInvalidUpload = errors.New("invalid result upload")
+)
+
+// UploadError lists the problems of the rows of a bulk result upload.
+// Nothing of an upload with problems is saved.
+type UploadError struct {
+	Problems []string
+}
+
+func (e *UploadError) Error() string {
+	return "invalid result upload: " + strings.Join(e.Problems, "; ")
+}
+
+type Service struct {
+	repo   Repository
+	drives DriveReader
+	authz  *auth.Authorizer
+}
+
+func NewService(repo Repository, drives DriveReader, authz *auth.Authorizer) *Service {
+	return &Service{
+		repo:   repo,
+		drives: drives,
+		authz:  authz,
+	}
+}
+
+func (s *Service) ListRounds(principal *auth.Principal, driveID string) ([]*entity.Round, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationRead); err != nil {
+		return nil, err
+	}
+	if err := s.checkDrive(principal.InstitutionID, driveID); err != nil {
+		return nil, err
+	}
+
+	rounds, err := s.repo.ListRounds(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to list rounds of drive %s, err=%v", driveID, err)
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// SetRounds replaces the rounds of a drive by the named ones, in order.
+// Rounds that are named again keep their results; rounds with results
+// cannot be dropped or moved.
+func (s *Service) SetRounds(principal *auth.Principal, driveID string, names []string) ([]*entity.Round, error) {
+	if err := s.authz.Authorize(principal, common.RoundManage); err != nil {
+		return nil, err
+	}
+	if err := s.checkDrive(principal.InstitutionID, driveID); err != nil {
+		return nil, err
+	}
+
+	rounds, err := s.repo.UpdateRounds(principal.InstitutionID, driveID, func(existing []*entity.Round, recorded map[string]bool) ([]*entity.Round, error) {
+		return entity.PlanRounds(principal.InstitutionID, driveID, existing, recorded, names)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// The drive was deleted since it was checked
+		return nil, ErrDriveNotFound
+	} else if errors.Is(err, entity.ErrInvalidRound) || errors.Is(err, entity.ErrRoundHasResults) {
+		return nil, err
+	} else if err != nil {
+		log.Printf("unable to replace rounds of drive %s, err=%v", driveID, err)
+		return nil, err
+	}
+	return rounds, nil
+}
+
+// RecordResults saves the results of a round for the applicants with the
+// given roll numbers and returns how many were saved. Only candidates
+// shortlisted in the previous round can get a result, and a candidate
+// with a result in the next round must stay shortlisted. Either every
+// entry is saved or none is.
+func (s *Service) RecordResults(principal *auth.Principal, roundID string, entries []entity.ResultEntry) (int, error) {
+	if err := s.authz.Authorize(principal, common.RoundManage); err != nil {
+		return 0, err
+	}
+	if len(entries) == 0 {
+		return 0, fmt.Errorf("%w: no results", ErrInvalidUpload)
+	} else if len(entries) > maxUploadRows {
+		return 0, fmt.Errorf("%w: at most %d results can be uploaded at once", ErrInvalidUpload, maxUploadRows)
+	}
+
+	round, err := s.getRound(principal.InstitutionID, roundID)
+	if err != nil {
+		return 0, err
+	}
+
+	// The checks run on the rounds and results as locked for the save, so
+	// concurrent uploads for neighbouring rounds cannot both pass them
+	saved, err := s.repo.RecordResults(principal.InstitutionID, round.DriveID, func(rounds []*entity.Round, applications map[string]*entity.Application, driveResults []*entity.RoundResult) ([]*entity.RoundResult, error) {
+		return buildResults(principal.UserID, roundID, entries, rounds, applications, driveResults)
+	})
+	if errors.Is(err, sql.ErrNoRows) {
+		// The drive, and with it the round, was deleted since it was read
+		return 0, ErrRoundNotFound
+	} else if errors.Is(err, ErrRoundNotFound) || errors.As(err, new(*UploadError)) {
+		return 0, err
+	} else if err != nil {
+		log.Printf("unable to save results of round %s, err=%v", roundID, err)
+		return 0, err
+	}
+	return saved, nil
+}
+
+// buildResults checks the entries of an upload for a round against the
+// rounds, active applications and recorded results of its drive.
+func buildResults(recordedBy, roundID string, entries []entity.ResultEntry, rounds []*entity.Round,
+	applications map[string]*entity.Application, driveResults []*entity.RoundResult) ([]*entity.RoundResult, error) {
+	i := slices.IndexFunc(rounds, func(r *entity.Round) bool { return r.RoundID == roundID })
+	if i < 0 {
+		// Dropped since it was read
+		return nil, ErrRoundNotFound
+	}
+	round := rounds[i]
+	var previous, next *entity.Round
+	if i > 0 {
+		previous = rounds[i-1]
+	}
+	if i+1 < len(rounds) {
+		next = rounds[i+1]
+	}
+
+	shortlisted := make(map[string]bool)
+	recordedNext := make(map[string]bool)
+	for _, result := range driveResults {
+		if previous != nil && result.RoundID == previous.RoundID && result.Survived() {
+			shortlisted[result.ApplicationID] = true
+		}
+		if next != nil && result.RoundID == next.RoundID {
+			recordedNext[result.ApplicationID] = true
+		}
+	}
+
+	var problems []string
+	results := make([]*entity.RoundResult, 0, len(entries))
+	rows := make(map[string]int, len(entries))
+	for _, entry := range entries {
+		// Roll numbers are stored upper case
+		rollNumber := strings.ToUpper(strings.TrimSpace(entry.RollNumber))
+		if row, ok := rows[rollNumber]; ok {
+			problems = append(problems, fmt.Sprintf("row %d: %s is already in row %d", entry.Row, rollNumber, row))
+			continue
+		}
+		rows[rollNumber] = entry.Row
+
+		application, ok := applications[rollNumber]
+		if !ok {
+			problems = append(problems, fmt.Sprintf("row %d: %s has not applied to this drive", entry.Row, rollNumber))
+			continue
+		}
+		if previous != nil && !shortlisted[application.ApplicationID] {
+			problems = append(problems, fmt.Sprintf("row %d: %s was not shortlisted in %s", entry.Row, rollNumber, previous.Name))
+			continue
+		}
+		if next != nil && entry.Status != entity.ResultShortlisted && recordedNext[application.ApplicationID] {
+			problems = append(problems, fmt.Sprintf("row %d: %s already has a result in %s", entry.Row, rollNumber, next.Name))
+			continue
+		}
+
+		result, err := entity.NewRoundResult(round.RoundID, application.ApplicationID, entry.Status, entry.Score, recordedBy)
+		if err != nil {
+			problems = append(problems, fmt.Sprintf("row %d: %v", entry.Row, err))
+			continue
+		}
+		results = append(results, result)
+	}
+	if len(problems) > 0 {
+		return nil, &UploadError{Problems: problems}
+	}
+	return results, nil
+}
+
+func (s *Service) ListRoundResults(principal *auth.Principal, roundID string) ([]*entity.CandidateResult, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationRead); err != nil {
+		return nil, err
+	}
+	if _, err := s.getRound(principal.InstitutionID, roundID); err != nil {
+		return nil, err
+	}
+
+	results, err := s.repo.ListRoundResults(principal.InstitutionID, roundID)
+	if err != nil {
+		log.Printf("unable to list results of round %s, err=%v", roundID, err)
+		return nil, err
+	}
+	return results, nil
+}
+
+// GetProgression returns the number of active applicants of a drive and
+// how many of them made it through each round.
+func (s *Service) GetProgression(principal *auth.Principal, driveID string) (int, []entity.RoundProgress, error) {
+	if err := s.authz.Authorize(principal, common.ApplicationRead); err != nil {
+		return 0, nil, err
+	}
+	if err := s.checkDrive(principal.InstitutionID, driveID); err != nil {
+		return 0, nil, err
+	}
+
+	rounds, err := s.repo.ListRounds(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to list rounds of drive %s, err=%v", driveID, err)
+		return 0, nil, err
+	}
+	applications, err := s.repo.GetActiveApplications(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to get applications of drive %s, err=%v", driveID, err)
+		return 0, nil, err
+	}
+	results, err := s.repo.ListDriveResults(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to list results of drive %s, err=%v", driveID, err)
+		return 0, nil, err
+	}
+
+	applicationIDs := make([]string, 0, len(applications))
+	for _, application := range applications {
+		applicationIDs = append(applicationIDs, application.ApplicationID)
+	}
+	return len(applicationIDs), entity.Progression(rounds, applicationIDs, results), nil
+}
+
+func (s *Service) checkDrive(institutionID, driveID string) error {
+	if _, err := s.drives.GetDrive(institutionID, driveID); errors.Is(err, dataRepository.ErrNotFound) {
+		return ErrDriveNotFound
+	} else if err != nil {
+		log.Printf("unable to get drive %s, err=%v", driveID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) getRound(institutionID, roundID string) (*entity.Round, error) {
+	round, err := s.repo.GetRound(institutionID, roundID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRoundNotFound
+	} else if err != nil {
+		log.Printf("unable to get round %s, err=%v", roundID, err)
+		return nil, err
+	}
+	return round, nil
+}
@@ -0,0 +1,93 @@
+package entity
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"time"
+)
+
+// Actions recorded in the company audit trail
+const (
+	AuditCreate  = "create"
+	AuditUpdate  = "update"
+	AuditApprove = "approve"
+	AuditReject  = "reject"
+	// AuditReassign records a company handed to a new owner
+	AuditReassign = "reassign"
+	// Drive actions are recorded in the trail of the drive's company
+	AuditDriveCreate = "drive_create"
+	AuditDriveUpdate = "drive_update"
+	AuditDriveDelete = "drive_delete"
+)
+
+// AuditEntry records one write to company data. Entries form a hash chain:
+// each hash covers the entry and the hash of the entry before it, so editing
+// or removing an entry breaks every hash after it. Hashes are HMACs under a
+// key kept outside the database, so someone with write access to the
+// database cannot recompute the chain after editing it.
+type AuditEntry struct {
+	EntryID       int64
+	InstitutionID string
+	CompanyID     string
+	ActorID       string
+	Action        string
+	// Before and After are JSON snapshots of the written row, null when
+	// there was no row before or after the write
+	Before    string
+	After     string
+	CreatedAt time.Time
+	PrevHash  string
+	Hash      string
+}
+
+// NewAuditEntry snapshots before and after, either of which may be nil.
+func NewAuditEntry(institutionID, companyID, actorID, action string, before, after any) (*AuditEntry, error) {
+	beforeJSON, err := json.Marshal(before)
+	if err != nil {
+		return nil, err
+	}
+	afterJSON, err := json.Marshal(after)
+	if err != nil {
+		return nil, err
+	}
+	return &AuditEntry{
+		InstitutionID: institutionID,
+		CompanyID:     companyID,
+		ActorID:       actorID,
+		Action:        action,
+		Before:        string(beforeJSON),
+		After:         string(afterJSON),
+		// The database keeps microseconds, which the hash must match
+		CreatedAt: time.Now().UTC().Truncate(time.Microsecond),
+	}, nil
+}
+
+// Seal links the entry to the hash of the previous entry, which is empty
+// for the first one.
+func (e *AuditEntry) Seal(key []byte, prevHash string) {
+	e.PrevHash = prevHash
+	e.Hash = e.ComputeHash(key)
+}
+
+// ComputeHash returns the HMAC-SHA256 under key of the entry's content and
+// its link to the previous entry.
+func (e *AuditEntry) ComputeHash(key []byte) string {
+	h := hmac.New(sha256.New, key)
+	for _, field := range []string{
+		e.PrevHash,
+		e.InstitutionID,
+		e.CompanyID,
+		e.ActorID,
+		e.Action,
+		e.Before,
+		e.After,
+		e.CreatedAt.UTC().Format(time.RFC3339Nano),
+	} {
+		// Length prefixes keep field boundaries unambiguous
+		h.Write([]byte(strconv.Itoa(len(field)) + ":" + field))
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
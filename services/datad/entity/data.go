@@ -9,21 +9,19 @@ type Data struct {
 
 type CompanyData struct {
 	CompanyID      string
+	InstitutionID  string
 	CompanyName    string
 	CompanyAddress string
-	Drive          string
-	TypeOfDrive    string
 	FollowUp       string
 	IsContacted    bool
 	Remarks        string
 	ContactDetails string
 	HRDetails      string
+	OwnerID        string
 }
 
 func NewCompany(companyName,
 	CompanyAddress,
-	Drive,
-	TypeOfDrive,
 	FollowUp,
 	Remarks,
 	ContactDetails,
@@ -34,8 +32,6 @@ func NewCompany(companyName,
 		CompanyID:      uuid.NewString(),
 		CompanyName:    companyName,
 		CompanyAddress: CompanyAddress,
-		Drive:          Drive,
-		TypeOfDrive:    TypeOfDrive,
 		FollowUp:       FollowUp,
 		IsContacted:    false,
 		Remarks:        Remarks,
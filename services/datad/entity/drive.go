@@ -0,0 +1,252 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Drive types
+const (
+	DriveOnCampus  = "on_campus"
+	DriveOffCampus = "off_campus"
+	DrivePool      = "pool"
+	DriveVirtual   = "virtual"
+)
+
+// DriveTypes lists every drive type
+var DriveTypes = []string{DriveOnCampus, DriveOffCampus, DrivePool, DriveVirtual}
+
+// Drive statuses. Completed and cancelled drives are final.
+const (
+	DriveScheduled = "scheduled"
+	DriveCompleted = "completed"
+	DriveCancelled = "cancelled"
+)
+
+var (
+	// ErrInvalidDrive is wrapped by every validation error of a drive.
+	ErrInvalidDrive = errors.New("invalid drive")
+	// ErrDriveFinished is returned when changing a completed or cancelled drive.
+	ErrDriveFinished = errors.New("drive is already completed or cancelled")
+	// ErrDriveHasApplications is returned when deleting a drive students
+	// applied to; such drives are cancelled instead.
+	ErrDriveHasApplications = errors.New("drive has applications")
+)
+
+// Drive is one recruitment drive of a company. A company can hold several
+// drives, for example one per season or per campus.
+type Drive struct {
+	DriveID       string
+	InstitutionID string
+	CompanyID     string
+	JobRoles      []string
+	// CTC is the annual cost to company and Stipend the monthly internship
+	// stipend, both in whole rupees and 0 when not offered
+	CTC                  int64
+	Stipend              int64
+	Location             string
+	DriveType            string
+	RegistrationDeadline time.Time
+	DriveDate            time.Time
+	Eligibility          Eligibility
+	Status               string
+	CreatedBy            string
+	CreatedAt            time.Time
+	UpdatedAt            time.Time
+}
+
+// DriveDetails are the fields officers set when scheduling a drive.
+type DriveDetails struct {
+	JobRoles             []string
+	CTC                  int64
+	Stipend              int64
+	Location             string
+	DriveType            string
+	RegistrationDeadline time.Time
+	DriveDate            time.Time
+	Eligibility          Eligibility
+}
+
+// Eligibility lists the students who may apply to a drive. Zero values do
+// not restrict anything; MaxBacklogs is nil when any number is accepted.
+type Eligibility struct {
+	MinCGPA     float64
+	Branches    []string
+	MaxBacklogs *int
+	Batch       int
+}
+
+// Unmet describes every criterion a student with the given academic record
+// does not meet, and is empty when the student is eligible.
+func (e Eligibility) Unmet(cgpa float64, branch string, backlogs, batch int) []string {
+	var unmet []string
+	if cgpa < e.MinCGPA {
+		unmet = append(unmet, fmt.Sprintf("cgpa %.2f is below the minimum of %.2f", cgpa, e.MinCGPA))
+	}
+	if len(e.Branches) > 0 && !slices.ContainsFunc(e.Branches, func(b string) bool { return strings.EqualFold(b, branch) }) {
+		unmet = append(unmet, fmt.Sprintf("branch %s is not one of %s", branch, strings.Join(e.Branches, ", ")))
+	}
+	if e.MaxBacklogs != nil && backlogs > *e.MaxBacklogs {
+		unmet = append(unmet, fmt.Sprintf("%d backlogs exceed the maximum of %d", backlogs, *e.MaxBacklogs))
+	}
+	if e.Batch != 0 && batch != e.Batch {
+		unmet = append(unmet, fmt.Sprintf("batch %d is not %d", batch, e.Batch))
+	}
+	return unmet
+}
+
+func NewDrive(institutionID, companyID, createdBy string, details DriveDetails) (*Drive, error) {
+	now := time.Now()
+	drive := &Drive{
+		DriveID:       uuid.NewString(),
+		InstitutionID: institutionID,
+		CompanyID:     companyID,
+		Status:        DriveScheduled,
+		CreatedBy:     createdBy,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+	drive.apply(details)
+
+	if err := drive.validate(); err != nil {
+		return nil, err
+	}
+
+	return drive, nil
+}
+
+// Update replaces the details of a scheduled drive, keeping the old ones if
+// the new ones are invalid.
+func (d *Drive) Update(details DriveDetails) error {
+	if d.Status != DriveScheduled {
+		return ErrDriveFinished
+	}
+	updated := *d
+	updated.apply(details)
+	if err := updated.validate(); err != nil {
+		return err
+	}
+	updated.UpdatedAt = time.Now()
+	*d = updated
+	return nil
+}
+
+// SetStatus completes or cancels a scheduled drive.
+func (d *Drive) SetStatus(status string) error {
+	if status != DriveScheduled && status != DriveCompleted && status != DriveCancelled {
+		return fmt.Errorf("%w: unknown status %q", ErrInvalidDrive, status)
+	}
+	if status == d.Status {
+		return nil
+	}
+	if d.Status != DriveScheduled {
+		return ErrDriveFinished
+	}
+	d.Status = status
+	d.UpdatedAt = time.Now()
+	return nil
+}
+
+// IsRegistrationOpen reports whether students can still register at t.
+func (d *Drive) IsRegistrationOpen(t time.Time) bool {
+	return d.Status == DriveScheduled && !t.After(d.RegistrationDeadline)
+}
+
+func (d *Drive) apply(details DriveDetails) {
+	d.JobRoles = make([]string, 0, len(details.JobRoles))
+	for _, role := range details.JobRoles {
+		if role = strings.TrimSpace(role); role != "" && !slices.Contains(d.JobRoles, role) {
+			d.JobRoles = append(d.JobRoles, role)
+		}
+	}
+	d.CTC = details.CTC
+	d.Stipend = details.Stipend
+	d.Location = strings.TrimSpace(details.Location)
+	d.DriveType = details.DriveType
+	// The database keeps whole seconds
+	d.RegistrationDeadline = details.RegistrationDeadline.Truncate(time.Second)
+	d.DriveDate = details.DriveDate.Truncate(time.Second)
+
+	d.Eligibility = Eligibility{
+		// The database keeps two decimals
+		MinCGPA:  math.Round(details.Eligibility.MinCGPA*100) / 100,
+		Branches: make([]string, 0, len(details.Eligibility.Branches)),
+		Batch:    details.Eligibility.Batch,
+	}
+	for _, branch := range details.Eligibility.Branches {
+		branch = strings.TrimSpace(branch)
+		if branch != "" && !slices.ContainsFunc(d.Eligibility.Branches, func(b string) bool { return strings.EqualFold(b, branch) }) {
+			d.Eligibility.Branches = append(d.Eligibility.Branches, branch)
+		}
+	}
+	if details.Eligibility.MaxBacklogs != nil {
+		maxBacklogs := *details.Eligibility.MaxBacklogs
+		d.Eligibility.MaxBacklogs = &maxBacklogs
+	}
+}
+
+func (d *Drive) validate() error {
+	if d.CompanyID == "" {
+		return fmt.Errorf("%w: company cannot be empty", ErrInvalidDrive)
+	}
+	if len(d.JobRoles) == 0 {
+		return fmt.Errorf("%w: at least one job role is required", ErrInvalidDrive)
+	}
+	for _, role := range d.JobRoles {
+		if len(role) > 100 {
+			return fmt.Errorf("%w: job role %q is longer than 100 characters", ErrInvalidDrive, role)
+		}
+	}
+	if d.CTC < 0 || d.Stipend < 0 {
+		return fmt.Errorf("%w: ctc and stipend cannot be negative", ErrInvalidDrive)
+	}
+	if d.CTC == 0 && d.Stipend == 0 {
+		return fmt.Errorf("%w: either ctc or stipend is required", ErrInvalidDrive)
+	}
+	if len(d.Location) > 255 {
+		return fmt.Errorf("%w: location cannot be longer than 255 characters", ErrInvalidDrive)
+	}
+	if !slices.Contains(DriveTypes, d.DriveType) {
+		return fmt.Errorf("%w: drive type must be one of %s", ErrInvalidDrive, strings.Join(DriveTypes, ", "))
+	}
+	if d.DriveDate.IsZero() {
+		return fmt.Errorf("%w: drive date is required", ErrInvalidDrive)
+	}
+	if d.RegistrationDeadline.IsZero() {
+		return fmt.Errorf("%w: registration deadline is required", ErrInvalidDrive)
+	}
+	if d.RegistrationDeadline.After(d.DriveDate) {
+		return fmt.Errorf("%w: registration must close before the drive", ErrInvalidDrive)
+	}
+	if d.Eligibility.MinCGPA < 0 || d.Eligibility.MinCGPA > 10 {
+		return fmt.Errorf("%w: minimum cgpa must be between 0 and 10", ErrInvalidDrive)
+	}
+	for _, branch := range d.Eligibility.Branches {
+		if len(branch) > 100 {
+			return fmt.Errorf("%w: branch %q is longer than 100 characters", ErrInvalidDrive, branch)
+		}
+	}
+	if d.Eligibility.MaxBacklogs != nil && *d.Eligibility.MaxBacklogs < 0 {
+		return fmt.Errorf("%w: maximum backlogs cannot be negative", ErrInvalidDrive)
+	}
+	if d.Eligibility.Batch != 0 && (d.Eligibility.Batch < 2000 || d.Eligibility.Batch > 2100) {
+		return fmt.Errorf("%w: batch must be a graduation year", ErrInvalidDrive)
+	}
+	return nil
+}
+
+// DriveFilter selects drives of an institution. From keeps drives on or
+// after that time, listed soonest first; otherwise the latest come first.
+type DriveFilter struct {
+	InstitutionID string
+	CompanyID     string
+	Status        string
+	DriveType     string
+	From          *time.Time
+}
@@ -1,6 +1,8 @@
 package handler
 
 import (
+	"backend/pkg/auth"
+	"backend/services/datad/entity"
 	"backend/services/datad/presenter"
 	dataRepository "backend/services/datad/repository"
 	"backend/services/datad/usecase/data"
@@ -18,6 +20,24 @@ func getDataHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 }
 
+// errorStatus maps usecase errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, dataRepository.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, entity.ErrInvalidDrive):
+		return http.StatusBadRequest
+	case errors.Is(err, entity.ErrDriveFinished), errors.Is(err, entity.ErrDriveHasApplications):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
 func createCompany(service data.Usecase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		if r.Method != http.MethodPost {
@@ -33,11 +53,9 @@ func createCompany(service data.Usecase) http.HandlerFunc {
 		}
 
 		compnayID, err := service.CreateCompany(
-			req.JWT,
+			auth.FromContext(r.Context()),
 			req.CompanyName,
 			req.CompanyAddress,
-			req.Drive,
-			req.TypeOfDrive,
 			req.FollowUp,
 			req.Remarks,
 			req.ContactDetails,
@@ -45,7 +63,7 @@ func createCompany(service data.Usecase) http.HandlerFunc {
 			req.IsContacted)
 		if err != nil {
 			log.Printf("Unable to create company, err=%v", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -86,13 +104,12 @@ func getCompany(service data.Usecase) http.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement proper JWT extraction and validation if needed for GET
-		jwtString := "" // Placeholder: Pass empty JWT for now
-
-		company, err := service.GetCompany(jwtString, id) // Use the 'id' variable from the path
+		company, err := service.GetCompany(auth.FromContext(r.Context()), id)
 		if err != nil {
 			if errors.Is(err, dataRepository.ErrNotFound) {
 				http.Error(w, "Company not found", http.StatusNotFound)
+			} else if status := errorStatus(err); status != http.StatusInternalServerError {
+				http.Error(w, err.Error(), status)
 			} else {
 				log.Printf("Unable to get company, err=%v", err)
 				http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -105,13 +122,12 @@ func getCompany(service data.Usecase) http.HandlerFunc {
 			CompanyID:      company.CompanyID,
 			CompanyName:    company.CompanyName,
 			CompanyAddress: company.CompanyAddress,
-			Drive:          company.Drive,
-			TypeOfDrive:    company.TypeOfDrive,
 			FollowUp:       company.FollowUp,
 			IsContacted:    company.IsContacted,
 			Remarks:        company.Remarks,
 			ContactDetails: company.ContactDetails,
 			HRDetails:      company.HRDetails,
+			OwnerID:        company.OwnerID,
 		}); err != nil {
 			log.Printf("Unable to encode response, err=%v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -141,13 +157,12 @@ func getCompanyByName(service data.Usecase) http.HandlerFunc {
 			return
 		}
 
-		// TODO: Implement proper JWT extraction and validation if needed for GET
-		jwtString := "" // Placeholder: Pass empty JWT for now
-
-		company, err := service.GetCompany(jwtString, name) // Use the 'id' variable from the path
+		company, err := service.GetCompany(auth.FromContext(r.Context()), name)
 		if err != nil {
 			if errors.Is(err, dataRepository.ErrNotFound) {
 				http.Error(w, "Company not found", http.StatusNotFound)
+			} else if status := errorStatus(err); status != http.StatusInternalServerError {
+				http.Error(w, err.Error(), status)
 			} else {
 				log.Printf("Unable to get company, err=%v", err)
 				http.Error(w, "Internal server error", http.StatusInternalServerError)
@@ -160,13 +175,12 @@ func getCompanyByName(service data.Usecase) http.HandlerFunc {
 			CompanyID:      company.CompanyID,
 			CompanyName:    company.CompanyName,
 			CompanyAddress: company.CompanyAddress,
-			Drive:          company.Drive,
-			TypeOfDrive:    company.TypeOfDrive,
 			FollowUp:       company.FollowUp,
 			IsContacted:    company.IsContacted,
 			Remarks:        company.Remarks,
 			ContactDetails: company.ContactDetails,
 			HRDetails:      company.HRDetails,
+			OwnerID:        company.OwnerID,
 		}); err != nil {
 			log.Printf("Unable to encode response, err=%v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -178,7 +192,7 @@ func getCompanyByName(service data.Usecase) http.HandlerFunc {
 func updateCompanyByID(service data.Usecase) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Extract ID from path /v1/data/id/{id}
-		path := strings.TrimPrefix(r.URL.Path, "/v1/data/name/")
+		path := strings.TrimPrefix(r.URL.Path, "/v1/data/id/")
 		if path == "" {
 			http.Error(w, "company ID is required in the path", http.StatusBadRequest)
 			return
@@ -199,12 +213,10 @@ func updateCompanyByID(service data.Usecase) http.HandlerFunc {
 		}
 
 		company, err := service.UpdateCompany(
-			req.JWT,
-			req.CompanyID,
+			auth.FromContext(r.Context()),
+			id,
 			req.CompanyName,
 			req.CompanyAddress,
-			req.Drive,
-			req.TypeOfDrive,
 			req.FollowUp,
 			req.Remarks,
 			req.ContactDetails,
@@ -212,7 +224,7 @@ func updateCompanyByID(service data.Usecase) http.HandlerFunc {
 			req.IsContacted)
 		if err != nil {
 			log.Printf("Unable to create company, err=%v", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -221,13 +233,12 @@ func updateCompanyByID(service data.Usecase) http.HandlerFunc {
 			CompanyID:      company.CompanyID,
 			CompanyName:    company.CompanyName,
 			CompanyAddress: company.CompanyAddress,
-			Drive:          company.Drive,
-			TypeOfDrive:    company.TypeOfDrive,
 			FollowUp:       company.FollowUp,
 			IsContacted:    company.IsContacted,
 			Remarks:        company.Remarks,
 			ContactDetails: company.ContactDetails,
 			HRDetails:      company.HRDetails,
+			OwnerID:        company.OwnerID,
 		}); err != nil {
 			log.Printf("Unable to encode response, err=%v", err)
 			http.Error(w, err.Error(), http.StatusInternalServerError)
@@ -252,10 +263,10 @@ func getAwaitingApproval(service data.Usecase) http.HandlerFunc {
 			return
 		}
 
-		companies, err := service.GetAwaitingApproval()
+		companies, err := service.GetAwaitingApproval(auth.FromContext(r.Context()))
 		if err != nil {
 			log.Printf("Unable to create company, err=%v", err)
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			http.Error(w, err.Error(), errorStatus(err))
 			return
 		}
 
@@ -291,13 +302,13 @@ func setAwaitingApproval(service data.Usecase) http.HandlerFunc {
 			return
 		}
 
-		_, err := service.SetAwaitingApproval(req.JWT, id, req.IsApproved)
+		_, err := service.SetAwaitingApproval(auth.FromContext(r.Context()), id, req.IsApproved)
 		if err != nil {
 			if errors.Is(err, dataRepository.ErrNotFound) {
 				http.Error(w, "Company not found", http.StatusNotFound)
 			} else {
 				log.Printf("Unable to create company, err=%v", err)
-				http.Error(w, err.Error(), http.StatusInternalServerError)
+				http.Error(w, err.Error(), errorStatus(err))
 			}
 			return
 		}
@@ -309,11 +320,61 @@ func setAwaitingApproval(service data.Usecase) http.HandlerFunc {
 	}
 }
 
+func getCompanyAudit(service data.Usecase, companyID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if _, err := uuid.Parse(companyID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid company ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		entries, err := service.GetCompanyAudit(auth.FromContext(r.Context()), companyID)
+		if err != nil {
+			log.Printf("Unable to get company audit history, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.AuditEntryResponse, 0, len(entries))
+		for _, entry := range entries {
+			resp = append(resp, presenter.AuditEntryResponse{
+				EntryID:   entry.EntryID,
+				CompanyID: entry.CompanyID,
+				ActorID:   entry.ActorID,
+				Action:    entry.Action,
+				Before:    json.RawMessage(entry.Before),
+				After:     json.RawMessage(entry.After),
+				CreatedAt: entry.CreatedAt.Unix(),
+				PrevHash:  entry.PrevHash,
+				Hash:      entry.Hash,
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			log.Printf("Unable to encode response, err=%v", err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
 // Register Data Routes
-func RegisterDataHandlers(service data.Usecase) {
-	http.HandleFunc("/v1/data/health", getDataHealth)   // GET
-	http.HandleFunc("/v1/data", createCompany(service)) // POST
-	http.HandleFunc("/v1/data/id/", func(w http.ResponseWriter, r *http.Request) {
+func RegisterDataHandlers(service data.Usecase, authn *auth.Authenticator) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.Handle(pattern, authn.Middleware(handler))
+	}
+
+	handle("/v1/data/health", getDataHealth)   // GET
+	handle("/v1/data", createCompany(service)) // POST
+	handle("/v1/data/id/", func(w http.ResponseWriter, r *http.Request) {
+		if companyID, ok := strings.CutSuffix(strings.TrimPrefix(r.URL.Path, "/v1/data/id/"), "/audit"); ok {
+			if r.Method != http.MethodGet {
+				http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+				return
+			}
+			getCompanyAudit(service, companyID)(w, r) //v1/data/id/{id}/audit // GET
+			return
+		}
 		switch r.Method {
 		case http.MethodGet:
 			getCompany(service)(w, r) // GET
@@ -323,7 +384,7 @@ func RegisterDataHandlers(service data.Usecase) {
 			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
 		}
 	})
-	http.HandleFunc("/v1/data/name/", getCompanyByName(service))          // POST
-	http.HandleFunc("/v1/data/approve", getAwaitingApproval(service))     // GET
-	http.HandleFunc("/v1/data/approve/id/", setAwaitingApproval(service)) // GET
+	handle("/v1/data/name/", getCompanyByName(service))          // POST
+	handle("/v1/data/approve", getAwaitingApproval(service))     // GET
+	handle("/v1/data/approve/id/", setAwaitingApproval(service)) // GET
 }
@@ -0,0 +1,273 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/datad/entity"
+	"backend/services/datad/presenter"
+	"backend/services/datad/usecase/drive"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func toDriveResponse(d *entity.Drive) presenter.DriveResponse {
+	return presenter.DriveResponse{
+		DriveID:              d.DriveID,
+		CompanyID:            d.CompanyID,
+		JobRoles:             d.JobRoles,
+		CTC:                  d.CTC,
+		Stipend:              d.Stipend,
+		Location:             d.Location,
+		DriveType:            d.DriveType,
+		RegistrationDeadline: d.RegistrationDeadline.Unix(),
+		DriveDate:            d.DriveDate.Unix(),
+		Eligibility: presenter.Eligibility{
+			MinCGPA:     d.Eligibility.MinCGPA,
+			Branches:    d.Eligibility.Branches,
+			MaxBacklogs: d.Eligibility.MaxBacklogs,
+			Batch:       d.Eligibility.Batch,
+		},
+		Status:           d.Status,
+		RegistrationOpen: d.IsRegistrationOpen(time.Now()),
+		CreatedBy:        d.CreatedBy,
+		CreatedAt:        d.CreatedAt.Unix(),
+		UpdatedAt:        d.UpdatedAt.Unix(),
+	}
+}
+
+func toDriveDetails(req presenter.DriveRequest) entity.DriveDetails {
+	details := entity.DriveDetails{
+		JobRoles:  req.JobRoles,
+		CTC:       req.CTC,
+		Stipend:   req.Stipend,
+		Location:  req.Location,
+		DriveType: req.DriveType,
+		Eligibility: entity.Eligibility{
+			MinCGPA:     req.Eligibility.MinCGPA,
+			Branches:    req.Eligibility.Branches,
+			MaxBacklogs: req.Eligibility.MaxBacklogs,
+			Batch:       req.Eligibility.Batch,
+		},
+	}
+	// Zero stays the zero time, which validation reports as missing
+	if req.RegistrationDeadline != 0 {
+		details.RegistrationDeadline = time.Unix(req.RegistrationDeadline, 0)
+	}
+	if req.DriveDate != 0 {
+		details.DriveDate = time.Unix(req.DriveDate, 0)
+	}
+	return details
+}
+
+func writeDrive(w http.ResponseWriter, d *entity.Drive) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(toDriveResponse(d)); err != nil {
+		log.Printf("Unable to encode response, err=%v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func writeDrives(w http.ResponseWriter, drives []*entity.Drive) {
+	resp := make([]presenter.DriveResponse, 0, len(drives))
+	for _, d := range drives {
+		resp = append(resp, toDriveResponse(d))
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(resp); err != nil {
+		log.Printf("Unable to encode response, err=%v", err)
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+}
+
+func createDrive(service drive.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.DriveRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Unable to decode request body, err=%v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if _, err := uuid.Parse(req.CompanyID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid company ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		created, err := service.CreateDrive(auth.FromContext(r.Context()), req.CompanyID, toDriveDetails(req))
+		if err != nil {
+			log.Printf("Unable to create drive, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		writeDrive(w, created)
+	}
+}
+
+// listDrives serves GET /v1/drives?companyID=&status=&driveType=
+func listDrives(service drive.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		filter := entity.DriveFilter{
+			CompanyID: query.Get("companyID"),
+			Status:    query.Get("status"),
+			DriveType: query.Get("driveType"),
+		}
+		if filter.CompanyID != "" {
+			if _, err := uuid.Parse(filter.CompanyID); err != nil {
+				http.Error(w, fmt.Sprintf("invalid company ID format: %v", err), http.StatusBadRequest)
+				return
+			}
+		}
+		switch filter.Status {
+		case "", entity.DriveScheduled, entity.DriveCompleted, entity.DriveCancelled:
+		default:
+			http.Error(w, fmt.Sprintf("unknown status %q", filter.Status), http.StatusBadRequest)
+			return
+		}
+		if filter.DriveType != "" && !slices.Contains(entity.DriveTypes, filter.DriveType) {
+			http.Error(w, fmt.Sprintf("unknown drive type %q", filter.DriveType), http.StatusBadRequest)
+			return
+		}
+
+		drives, err := service.ListDrives(auth.FromContext(r.Context()), filter)
+		if err != nil {
+			log.Printf("Unable to list drives, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		writeDrives(w, drives)
+	}
+}
+
+func listUpcomingDrives(service drive.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		drives, err := service.ListUpcomingDrives(auth.FromContext(r.Context()))
+		if err != nil {
+			log.Printf("Unable to list upcoming drives, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		writeDrives(w, drives)
+	}
+}
+
+func getDrive(service drive.Usecase, driveID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		found, err := service.GetDrive(auth.FromContext(r.Context()), driveID)
+		if err != nil {
+			log.Printf("Unable to get drive, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		writeDrive(w, found)
+	}
+}
+
+func updateDrive(service drive.Usecase, driveID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.DriveRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Unable to decode request body, err=%v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := service.UpdateDrive(auth.FromContext(r.Context()), driveID, toDriveDetails(req))
+		if err != nil {
+			log.Printf("Unable to update drive, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		writeDrive(w, updated)
+	}
+}
+
+func setDriveStatus(service drive.Usecase, driveID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.DriveStatusRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			log.Printf("Unable to decode request body, err=%v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := service.SetDriveStatus(auth.FromContext(r.Context()), driveID, req.Status)
+		if err != nil {
+			log.Printf("Unable to set drive status, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		writeDrive(w, updated)
+	}
+}
+
+func deleteDrive(service drive.Usecase, driveID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := service.DeleteDrive(auth.FromContext(r.Context()), driveID); err != nil {
+			log.Printf("Unable to delete drive, err=%v", err)
+			http.Error(w, err.Error(), errorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Register Drive Routes
+func RegisterDriveHandlers(service drive.Usecase, authn *auth.Authenticator) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.Handle(pattern, authn.Middleware(handler))
+	}
+
+	handle("/v1/drives", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listDrives(service)(w, r) // GET
+		case http.MethodPost:
+			createDrive(service)(w, r) // POST
+		default:
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	handle("/v1/drives/upcoming", listUpcomingDrives(service)) // GET
+	handle("/v1/drives/", func(w http.ResponseWriter, r *http.Request) {
+		// Split /v1/drives/{id}/{action}
+		id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/drives/"), "/"), "/")
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, fmt.Sprintf("invalid drive ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			getDrive(service, id)(w, r) //v1/drives/{id} // GET
+		case action == "" && r.Method == http.MethodPut:
+			updateDrive(service, id)(w, r) //v1/drives/{id} // PUT
+		case action == "" && r.Method == http.MethodDelete:
+			deleteDrive(service, id)(w, r) //v1/drives/{id} // DELETE
+		case action == "status":
+			setDriveStatus(service, id)(w, r) //v1/drives/{id}/status // PUT
+		case action == "":
+			http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+}
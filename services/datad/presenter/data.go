@@ -1,12 +1,11 @@
 package presenter
 
+import "encoding/json"
+
 type CreateCompanyRequest struct {
-	JWT            string `json:"jwt"`
 	CompanyID      string `json:"companyID"`
 	CompanyName    string `json:"companyName"`
 	CompanyAddress string `json:"companyAddress"`
-	Drive          string `json:"drive"`
-	TypeOfDrive    string `json:"typeOfDrive"`
 	FollowUp       string `json:"followUp"`
 	IsContacted    bool   `json:"isContacted"`
 	Remarks        string `json:"remarks"`
@@ -18,27 +17,36 @@ type GetCompanyResponse struct {
 	CompanyID      string `json:"companyID"`
 	CompanyName    string `json:"companyName"`
 	CompanyAddress string `json:"companyAddress"`
-	Drive          string `json:"drive"`
-	TypeOfDrive    string `json:"typeOfDrive"`
 	FollowUp       string `json:"followUp"`
 	IsContacted    bool   `json:"isContacted"`
 	Remarks        string `json:"remarks"`
 	ContactDetails string `json:"contactDetails"`
 	HRDetails      string `json:"hrDetails"`
-	IsApproved     *bool   `json:"isApproved"`
+	OwnerID        string `json:"ownerID,omitempty"`
+	IsApproved     *bool  `json:"isApproved"`
 }
 
 type GetCompanyRequest struct {
-	JWT string `json:"jwt"`
-	ID  string `json:"id"`
+	ID string `json:"id"`
 }
 
 type SetAwaitingApprovalRequest struct {
-	JWT string `json:"jwt"`
-	ID  string `json:"id"`
-	IsApproved bool `json:"isApproved"`
+	ID         string `json:"id"`
+	IsApproved bool   `json:"isApproved"`
 }
 
 type CreateCompanyResponse struct {
 	CompanyID string `json:"companyID"`
 }
+
+type AuditEntryResponse struct {
+	EntryID   int64           `json:"entryID"`
+	CompanyID string          `json:"companyID"`
+	ActorID   string          `json:"actorID"`
+	Action    string          `json:"action"`
+	Before    json.RawMessage `json:"before"`
+	After     json.RawMessage `json:"after"`
+	CreatedAt int64           `json:"createdAt"`
+	PrevHash  string          `json:"prevHash"`
+	Hash      string          `json:"hash"`
+}
@@ -0,0 +1,45 @@
+package presenter
+
+// DriveRequest holds times as Unix seconds, like every response.
+type DriveRequest struct {
+	CompanyID            string      `json:"companyID"`
+	JobRoles             []string    `json:"jobRoles"`
+	CTC                  int64       `json:"ctc"`
+	Stipend              int64       `json:"stipend"`
+	Location             string      `json:"location"`
+	DriveType            string      `json:"driveType"`
+	RegistrationDeadline int64       `json:"registrationDeadline"`
+	DriveDate            int64       `json:"driveDate"`
+	Eligibility          Eligibility `json:"eligibility"`
+}
+
+// Eligibility restricts who may apply to a drive. Omitted fields and a null
+// maxBacklogs do not restrict anything.
+type Eligibility struct {
+	MinCGPA     float64  `json:"minCGPA"`
+	Branches    []string `json:"branches"`
+	MaxBacklogs *int     `json:"maxBacklogs"`
+	Batch       int      `json:"batch"`
+}
+
+type DriveStatusRequest struct {
+	Status string `json:"status"`
+}
+
+type DriveResponse struct {
+	DriveID              string      `json:"driveID"`
+	CompanyID            string      `json:"companyID"`
+	JobRoles             []string    `json:"jobRoles"`
+	CTC                  int64       `json:"ctc"`
+	Stipend              int64       `json:"stipend"`
+	Location             string      `json:"location"`
+	DriveType            string      `json:"driveType"`
+	RegistrationDeadline int64       `json:"registrationDeadline"`
+	DriveDate            int64       `json:"driveDate"`
+	Eligibility          Eligibility `json:"eligibility"`
+	Status               string      `json:"status"`
+	RegistrationOpen     bool        `json:"registrationOpen"`
+	CreatedBy            string      `json:"createdBy"`
+	CreatedAt            int64       `json:"createdAt"`
+	UpdatedAt            int64       `json:"updatedAt"`
+}
@@ -0,0 +1,230 @@
+package data
+
+import (
+	"backend/services/datad/entity"
+	"database/sql"
+	"time"
+)
+
+// companySnapshot is the JSON form of a company row in audit entries.
+type companySnapshot struct {
+	CompanyID      string `json:"companyID"`
+	CompanyName    string `json:"companyName"`
+	CompanyAddress string `json:"companyAddress"`
+	FollowUp       string `json:"followUp"`
+	IsContacted    bool   `json:"isContacted"`
+	Remarks        string `json:"remarks"`
+	ContactDetails string `json:"contactDetails"`
+	HRDetails      string `json:"hrDetails"`
+	OwnerID        string `json:"ownerID,omitempty"`
+	IsApproved     *bool  `json:"isApproved,omitempty"`
+}
+
+// driveSnapshot is the JSON form of a drive in audit entries.
+type driveSnapshot struct {
+	DriveID              string    `json:"driveID"`
+	JobRoles             []string  `json:"jobRoles"`
+	CTC                  int64     `json:"ctc"`
+	Stipend              int64     `json:"stipend"`
+	Location             string    `json:"location"`
+	DriveType            string    `json:"driveType"`
+	RegistrationDeadline time.Time `json:"registrationDeadline"`
+	DriveDate            time.Time `json:"driveDate"`
+	MinCGPA              float64   `json:"minCGPA"`
+	Branches             []string  `json:"branches"`
+	MaxBacklogs          *int      `json:"maxBacklogs"`
+	Batch                int       `json:"batch"`
+	Status               string    `json:"status"`
+}
+
+func driveSnapshotOf(drive *entity.Drive) *driveSnapshot {
+	return &driveSnapshot{
+		DriveID:              drive.DriveID,
+		JobRoles:             drive.JobRoles,
+		CTC:                  drive.CTC,
+		Stipend:              drive.Stipend,
+		Location:             drive.Location,
+		DriveType:            drive.DriveType,
+		RegistrationDeadline: drive.RegistrationDeadline.UTC(),
+		DriveDate:            drive.DriveDate.UTC(),
+		MinCGPA:              drive.Eligibility.MinCGPA,
+		Branches:             drive.Eligibility.Branches,
+		MaxBacklogs:          drive.Eligibility.MaxBacklogs,
+		Batch:                drive.Eligibility.Batch,
+		Status:               drive.Status,
+	}
+}
+
+func snapshotOf(company *entity.CompanyData) *companySnapshot {
+	return &companySnapshot{
+		CompanyID:      company.CompanyID,
+		CompanyName:    company.CompanyName,
+		CompanyAddress: company.CompanyAddress,
+		FollowUp:       company.FollowUp,
+		IsContacted:    company.IsContacted,
+		Remarks:        company.Remarks,
+		ContactDetails: company.ContactDetails,
+		HRDetails:      company.HRDetails,
+		OwnerID:        company.OwnerID,
+	}
+}
+
+// lockCompany reads a company row of an institution for the rest of tx,
+// nil if there is none.
+func lockCompany(tx *sql.Tx, institutionID, id string) (*companySnapshot, error) {
+	var snapshot companySnapshot
+	var companyAddress, followUp, remarks, contactDetails, hrDetails, ownerID sql.NullString
+	var isContacted sql.NullBool
+	err := tx.QueryRow(`
+		SELECT
+			id, company_name, company_address,
+			follow_up, is_contacted, remarks, contact_details, hr_details, owner_id
+		FROM company_data
+		WHERE id = ? AND institution_id = ? FOR UPDATE
+	`, id, institutionID).Scan(&snapshot.CompanyID,
+		&snapshot.CompanyName,
+		&companyAddress,
+		&followUp,
+		&isContacted,
+		&remarks,
+		&contactDetails,
+		&hrDetails,
+		&ownerID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	snapshot.CompanyAddress = companyAddress.String
+	snapshot.FollowUp = followUp.String
+	snapshot.IsContacted = isContacted.Bool
+	snapshot.Remarks = remarks.String
+	snapshot.ContactDetails = contactDetails.String
+	snapshot.HRDetails = hrDetails.String
+	snapshot.OwnerID = ownerID.String
+	return &snapshot, nil
+}
+
+// lockApproval reads a pending edit of an institution for the rest of tx.
+func lockApproval(tx *sql.Tx, institutionID, id string) (*companySnapshot, error) {
+	var snapshot companySnapshot
+	var companyAddress, followUp, remarks, contactDetails, hrDetails sql.NullString
+	var isContacted, isApproved sql.NullBool
+	err := tx.QueryRow(`
+		SELECT
+			id, company_name, company_address,
+			follow_up, is_contacted, remarks, contact_details, hr_details, is_approved
+		FROM company_data_approval
+		WHERE id = ? AND institution_id = ? FOR UPDATE
+	`, id, institutionID).Scan(&snapshot.CompanyID,
+		&snapshot.CompanyName,
+		&companyAddress,
+		&followUp,
+		&isContacted,
+		&remarks,
+		&contactDetails,
+		&hrDetails,
+		&isApproved)
+	if err != nil {
+		return nil, err
+	}
+	snapshot.CompanyAddress = companyAddress.String
+	snapshot.FollowUp = followUp.String
+	snapshot.IsContacted = isContacted.Bool
+	snapshot.Remarks = remarks.String
+	snapshot.ContactDetails = contactDetails.String
+	snapshot.HRDetails = hrDetails.String
+	snapshot.IsApproved = &isApproved.Bool
+	return &snapshot, nil
+}
+
+// appendAudit chains an entry for a write made in tx onto the audit trail.
+// before and after are snapshots of the written row, nil when there was none.
+// Locking the head row serializes writers, so the chain never forks.
+func (r *Repository) appendAudit(tx *sql.Tx, institutionID, companyID, actorID, action string, before, after any) error {
+	entry, err := entity.NewAuditEntry(institutionID, companyID, actorID, action, before, after)
+	if err != nil {
+		return err
+	}
+
+	var prevHash string
+	if err := tx.QueryRow("SELECT hash FROM company_audit_head WHERE id = 1 FOR UPDATE;").Scan(&prevHash); err != nil {
+		return err
+	}
+	entry.Seal(r.auditKey, prevHash)
+
+	result, err := tx.Exec(`
+		INSERT INTO company_audit (institution_id, company_id, actor_id, action, before_json, after_json, created_at, prev_hash, hash)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`, entry.InstitutionID, entry.CompanyID, entry.ActorID, entry.Action, entry.Before, entry.After, entry.CreatedAt, entry.PrevHash, entry.Hash)
+	if err != nil {
+		return err
+	}
+	entryID, err := result.LastInsertId()
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("UPDATE company_audit_head SET entry_id = ?, hash = ? WHERE id = 1;", entryID, entry.Hash)
+	return err
+}
+
+// ListCompanyAudit returns the audit history of a company, oldest first.
+func (r *Repository) ListCompanyAudit(institutionID, companyID string) ([]*entity.AuditEntry, error) {
+	return r.listAudit(`
+		SELECT
+			entry_id, institution_id, company_id, actor_id, action, before_json, after_json, created_at, prev_hash, hash
+		FROM company_audit
+		WHERE institution_id = ? AND company_id = ?
+		ORDER BY entry_id;
+	`, institutionID, companyID)
+}
+
+// ListAuditEntries returns up to limit entries of the whole trail after afterID, in chain order.
+func (r *Repository) ListAuditEntries(afterID int64, limit int) ([]*entity.AuditEntry, error) {
+	return r.listAudit(`
+		SELECT
+			entry_id, institution_id, company_id, actor_id, action, before_json, after_json, created_at, prev_hash, hash
+		FROM company_audit
+		WHERE entry_id > ?
+		ORDER BY entry_id
+		LIMIT ?;
+	`, afterID, limit)
+}
+
+// GetAuditHead returns the last entry written to the trail and its hash.
+func (r *Repository) GetAuditHead() (int64, string, error) {
+	var entryID int64
+	var hash string
+	err := r.db.QueryRow("SELECT entry_id, hash FROM company_audit_head WHERE id = 1;").Scan(&entryID, &hash)
+	return entryID, hash, err
+}
+
+func (r *Repository) listAudit(query string, args ...any) ([]*entity.AuditEntry, error) {
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.AuditEntry
+	for rows.Next() {
+		var entry entity.AuditEntry
+		if err := rows.Scan(&entry.EntryID,
+			&entry.InstitutionID,
+			&entry.CompanyID,
+			&entry.ActorID,
+			&entry.Action,
+			&entry.Before,
+			&entry.After,
+			&entry.CreatedAt,
+			&entry.PrevHash,
+			&entry.Hash); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -11,43 +11,98 @@ var ErrNotFound = errors.New("entity not found")
 
 type Repository struct {
 	db *sql.DB
+	// auditKey keys the audit trail's hash chain
+	auditKey []byte
 }
 
-func NewDataRepository(db *sql.DB) *Repository {
+func NewDataRepository(db *sql.DB, auditKey []byte) *Repository {
 	return &Repository{
-		db: db,
+		db:       db,
+		auditKey: auditKey,
 	}
 }
 
-func (r *Repository) CreateCompany(company *entity.CompanyData) error {
-	query := `INSERT INTO company_data (id, company_name, company_address, drive, type_of_drive, follow_up, is_contacted, remarks, contact_details, hr_details) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := r.db.Exec(query, company.CompanyID, company.CompanyName, company.CompanyAddress, company.Drive, company.TypeOfDrive, company.FollowUp, company.IsContacted, company.Remarks, company.ContactDetails, company.HRDetails)
+// CreateCompany stores a company and records it in the audit trail as created by actorID.
+func (r *Repository) CreateCompany(company *entity.CompanyData, actorID string) error {
+	tx, err := r.db.Begin()
 	if err != nil {
 		return err
 	}
+	defer tx.Rollback()
+
+	query := `INSERT INTO company_data (id, institution_id, company_name, company_address, follow_up, is_contacted, remarks, contact_details, hr_details, owner_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err = tx.Exec(query, company.CompanyID, company.InstitutionID, company.CompanyName, company.CompanyAddress, company.FollowUp, company.IsContacted, company.Remarks, company.ContactDetails, company.HRDetails, company.OwnerID)
+	if err != nil {
+		return err
+	}
+	if err := r.appendAudit(tx, company.InstitutionID, company.CompanyID, actorID, entity.AuditCreate, nil, snapshotOf(company)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ReassignCompanies hands every company of an institution owned by
+// fromUserID to toUserID inside tx, recording each in the audit trail on
+// behalf of actorID. It lets callers that own tx, like user deletion, move
+// ownership without bypassing the trail.
+func (r *Repository) ReassignCompanies(tx *sql.Tx, institutionID, fromUserID, toUserID, actorID string) error {
+	rows, err := tx.Query("SELECT id FROM company_data WHERE owner_id = ? AND institution_id = ? ORDER BY id FOR UPDATE", fromUserID, institutionID)
+	if err != nil {
+		return err
+	}
+	var companyIDs []string
+	for rows.Next() {
+		var companyID string
+		if err := rows.Scan(&companyID); err != nil {
+			rows.Close()
+			return err
+		}
+		companyIDs = append(companyIDs, companyID)
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for _, companyID := range companyIDs {
+		before, err := lockCompany(tx, institutionID, companyID)
+		if err != nil {
+			return err
+		}
+		if _, err := tx.Exec("UPDATE company_data SET owner_id = ? WHERE id = ? AND institution_id = ?", toUserID, companyID, institutionID); err != nil {
+			return err
+		}
+		after := *before
+		after.OwnerID = toUserID
+		if err := r.appendAudit(tx, institutionID, companyID, actorID, entity.AuditReassign, before, &after); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
-func (r *Repository) GetCompany(id string) (*entity.CompanyData, error) {
+func (r *Repository) GetCompany(institutionID, id string) (*entity.CompanyData, error) {
 	var company entity.CompanyData
+	var ownerID sql.NullString
 	query := `
 		SELECT 
-			id, company_name, company_address, drive, type_of_drive, 
-			follow_up, is_contacted, remarks, contact_details, hr_details 
+			id, institution_id, company_name, company_address,
+			follow_up, is_contacted, remarks, contact_details, hr_details, owner_id 
 		FROM company_data 
-		WHERE id = ?
+		WHERE id = ? AND institution_id = ?
 	`
-	err := r.db.QueryRow(query, id).Scan(
+	err := r.db.QueryRow(query, id, institutionID).Scan(
 		&company.CompanyID,
+		&company.InstitutionID,
 		&company.CompanyName,
 		&company.CompanyAddress,
-		&company.Drive,
-		&company.TypeOfDrive,
 		&company.FollowUp,
 		&company.IsContacted,
 		&company.Remarks,
 		&company.ContactDetails,
 		&company.HRDetails,
+		&ownerID,
 	)
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
@@ -55,24 +110,24 @@ func (r *Repository) GetCompany(id string) (*entity.CompanyData, error) {
 		}
 		return nil, err
 	}
+	company.OwnerID = ownerID.String
 	return &company, nil
 }
 
-func (r *Repository) GetCompanyByName(name string) (*entity.CompanyData, error) {
+func (r *Repository) GetCompanyByName(institutionID, name string) (*entity.CompanyData, error) {
 	var company entity.CompanyData
 	query := `
 		SELECT 
-			id, company_name, company_address, drive, type_of_drive, 
+			id, institution_id, company_name, company_address,
 			follow_up, is_contacted, remarks, contact_details, hr_details 
 		FROM company_data 
-		WHERE name = ?
+		WHERE name = ? AND institution_id = ?
 	`
-	err := r.db.QueryRow(query, name).Scan(
+	err := r.db.QueryRow(query, name, institutionID).Scan(
 		&company.CompanyID,
+		&company.InstitutionID,
 		&company.CompanyName,
 		&company.CompanyAddress,
-		&company.Drive,
-		&company.TypeOfDrive,
 		&company.FollowUp,
 		&company.IsContacted,
 		&company.Remarks,
@@ -88,11 +143,28 @@ func (r *Repository) GetCompanyByName(name string) (*entity.CompanyData, error)
 	return &company, nil
 }
 
-func (r *Repository) UpdateCompany(company *entity.CompanyData) error {
+// UpdateCompany submits an edit of a company for approval, recording the
+// current and proposed values in the audit trail. Only companies of the
+// institution the edit is made in can be edited.
+func (r *Repository) UpdateCompany(company *entity.CompanyData, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockCompany(tx, company.InstitutionID, company.CompanyID)
+	if err != nil {
+		return err
+	}
+	if before == nil {
+		return ErrNotFound
+	}
+
 	query := `
 		INSERT INTO company_data_approval 
-		(id, company_name, company_address, drive, type_of_drive, follow_up, is_contacted, remarks, contact_details, hr_details)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		(id, institution_id, company_name, company_address, follow_up, is_contacted, remarks, contact_details, hr_details)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	// Handle optional HRDetails
@@ -101,32 +173,36 @@ func (r *Repository) UpdateCompany(company *entity.CompanyData) error {
 		Valid:  company.HRDetails != "",
 	}
 
-	_, err := r.db.Exec(query,
+	_, err = tx.Exec(query,
 		company.CompanyID,
+		company.InstitutionID,
 		company.CompanyName,
 		company.CompanyAddress,
-		company.Drive,
-		company.TypeOfDrive,
 		company.FollowUp,
 		company.IsContacted,
 		company.Remarks,
 		company.ContactDetails,
 		hrDetails,
 	)
-
-	return err
+	if err != nil {
+		return err
+	}
+	if err := r.appendAudit(tx, company.InstitutionID, company.CompanyID, actorID, entity.AuditUpdate, before, snapshotOf(company)); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
-func (r *Repository) GetAwaitingApproval() ([]*entity.CompanyData, error) {
+func (r *Repository) GetAwaitingApproval(institutionID string) ([]*entity.CompanyData, error) {
 	var companies []*entity.CompanyData
 	query := `
 		SELECT 
-			id, company_name, company_address, drive, type_of_drive, 
+			id, institution_id, company_name, company_address,
 			follow_up, is_contacted, remarks, contact_details, hr_details 
 		FROM company_data_approval 
-		WHERE is_contacted = false
+		WHERE is_contacted = false AND institution_id = ?
 	`
-	rows, err := r.db.Query(query)
+	rows, err := r.db.Query(query, institutionID)
 	if err != nil {
 		return nil, err
 	}
@@ -136,10 +212,9 @@ func (r *Repository) GetAwaitingApproval() ([]*entity.CompanyData, error) {
 		var company entity.CompanyData
 		err := rows.Scan(
 			&company.CompanyID,
+			&company.InstitutionID,
 			&company.CompanyName,
 			&company.CompanyAddress,
-			&company.Drive,
-			&company.TypeOfDrive,
 			&company.FollowUp,
 			&company.IsContacted,
 			&company.Remarks,
@@ -158,26 +233,41 @@ func (r *Repository) GetAwaitingApproval() ([]*entity.CompanyData, error) {
 	return companies, nil
 }
 
-func (r *Repository) SetAwaitingApproval(companyID string, isApproved bool) (*entity.CompanyData, error) {
+// SetAwaitingApproval approves or rejects a pending edit of an institution on behalf of actorID.
+func (r *Repository) SetAwaitingApproval(institutionID, companyID string, isApproved bool, actorID string) (*entity.CompanyData, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	before, err := lockApproval(tx, institutionID, companyID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound // The ID does not exist in the approval table
+	} else if err != nil {
+		return nil, err
+	}
+
 	query := `
 		UPDATE company_data_approval 
 		SET is_approved = ? 
-		WHERE id = ?
+		WHERE id = ? AND institution_id = ?
 	`
-
-	result, err := r.db.Exec(query, isApproved, companyID)
-	if err != nil {
+	if _, err := tx.Exec(query, isApproved, companyID, institutionID); err != nil {
 		return nil, err
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		// Log the error, but potentially continue if RowsAffected isn't critical
-		// log.Printf("Could not get rows affected: %v", err)
-		return nil, nil // Or return the error: return nil, err
+	after := *before
+	after.IsApproved = &isApproved
+	action := entity.AuditApprove
+	if !isApproved {
+		action = entity.AuditReject
 	}
-	if rowsAffected == 0 {
-		return nil, ErrNotFound // Return ErrNotFound if no rows were updated (ID didn't exist in approval table)
+	if err := r.appendAudit(tx, institutionID, companyID, actorID, action, before, &after); err != nil {
+		return nil, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
 	}
 
 	// Since we only updated the approval table, returning the main company data might be misleading.
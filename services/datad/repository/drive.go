@@ -0,0 +1,296 @@
+package data
+
+import (
+	"backend/services/datad/entity"
+	"database/sql"
+	"errors"
+)
+
+// CreateDrive stores a drive with its job roles and eligible branches and
+// records it in its company's audit trail as created by actorID.
+func (r *Repository) CreateDrive(drive *entity.Drive, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO drives
+		(drive_id, institution_id, company_id, ctc, stipend, location, drive_type, registration_deadline, drive_date,
+		min_cgpa, max_backlogs, eligible_batch, status, created_by, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`, drive.DriveID, drive.InstitutionID, drive.CompanyID, drive.CTC, drive.Stipend, drive.Location, drive.DriveType,
+		drive.RegistrationDeadline, drive.DriveDate, drive.Eligibility.MinCGPA, drive.Eligibility.MaxBacklogs,
+		drive.Eligibility.Batch, drive.Status, drive.CreatedBy, drive.CreatedAt, drive.UpdatedAt)
+	if err != nil {
+		return err
+	}
+	if err := insertDriveChildren(tx, drive); err != nil {
+		return err
+	}
+	if err := r.appendAudit(tx, drive.InstitutionID, drive.CompanyID, actorID, entity.AuditDriveCreate, nil, driveSnapshotOf(drive)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateDrive saves every field of a drive and replaces its job roles and
+// eligible branches, recording the change on behalf of actorID.
+func (r *Repository) UpdateDrive(drive *entity.Drive, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockDrive(tx, drive.InstitutionID, drive.DriveID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	result, err := tx.Exec(`
+		UPDATE drives SET
+			ctc = ?, stipend = ?, location = ?, drive_type = ?, registration_deadline = ?, drive_date = ?,
+			min_cgpa = ?, max_backlogs = ?, eligible_batch = ?, status = ?, updated_at = ?
+		WHERE drive_id = ? AND institution_id = ?
+	`, drive.CTC, drive.Stipend, drive.Location, drive.DriveType, drive.RegistrationDeadline, drive.DriveDate,
+		drive.Eligibility.MinCGPA, drive.Eligibility.MaxBacklogs, drive.Eligibility.Batch, drive.Status,
+		drive.UpdatedAt, drive.DriveID, drive.InstitutionID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	if _, err := tx.Exec("DELETE FROM drive_job_roles WHERE drive_id = ?", drive.DriveID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM drive_eligible_branches WHERE drive_id = ?", drive.DriveID); err != nil {
+		return err
+	}
+	if err := insertDriveChildren(tx, drive); err != nil {
+		return err
+	}
+	if err := r.appendAudit(tx, drive.InstitutionID, drive.CompanyID, actorID, entity.AuditDriveUpdate, driveSnapshotOf(before), driveSnapshotOf(drive)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func insertDriveChildren(tx *sql.Tx, drive *entity.Drive) error {
+	for position, jobRole := range drive.JobRoles {
+		if _, err := tx.Exec("INSERT INTO drive_job_roles (drive_id, position, job_role) VALUES (?, ?, ?)", drive.DriveID, position, jobRole); err != nil {
+			return err
+		}
+	}
+	for _, branch := range drive.Eligibility.Branches {
+		if _, err := tx.Exec("INSERT INTO drive_eligible_branches (drive_id, branch) VALUES (?, ?)", drive.DriveID, branch); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CountDriveApplications counts the applications to a drive, withdrawn ones
+// included.
+func (r *Repository) CountDriveApplications(institutionID, driveID string) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM applications WHERE drive_id = ? AND institution_id = ?", driveID, institutionID).Scan(&count)
+	return count, err
+}
+
+// DeleteDrive removes a drive and records it in its company's audit trail
+// as deleted by actorID.
+func (r *Repository) DeleteDrive(institutionID, driveID, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	before, err := lockDrive(tx, institutionID, driveID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	} else if err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM drives WHERE drive_id = ? AND institution_id = ?", driveID, institutionID); err != nil {
+		return err
+	}
+	if err := r.appendAudit(tx, institutionID, before.CompanyID, actorID, entity.AuditDriveDelete, driveSnapshotOf(before), nil); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// lockDrive reads a drive of an institution with its job roles and eligible
+// branches for the rest of tx.
+func lockDrive(tx *sql.Tx, institutionID, driveID string) (*entity.Drive, error) {
+	drive, err := scanDrive(tx.QueryRow(`
+		SELECT
+			drive_id, institution_id, company_id, ctc, stipend, location, drive_type,
+			registration_deadline, drive_date, min_cgpa, max_backlogs, eligible_batch, status, created_by, created_at, updated_at
+		FROM drives
+		WHERE drive_id = ? AND institution_id = ? FOR UPDATE
+	`, driveID, institutionID))
+	if err != nil {
+		return nil, err
+	}
+	if err := loadDriveChildren(tx, drive); err != nil {
+		return nil, err
+	}
+	return drive, nil
+}
+
+func (r *Repository) GetDrive(institutionID, driveID string) (*entity.Drive, error) {
+	drive, err := scanDrive(r.db.QueryRow(`
+		SELECT
+			drive_id, institution_id, company_id, ctc, stipend, location, drive_type,
+			registration_deadline, drive_date, min_cgpa, max_backlogs, eligible_batch, status, created_by, created_at, updated_at
+		FROM drives
+		WHERE drive_id = ? AND institution_id = ?
+	`, driveID, institutionID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	if err := loadDriveChildren(r.db, drive); err != nil {
+		return nil, err
+	}
+	return drive, nil
+}
+
+// querier runs queries on the database or inside a transaction.
+type querier interface {
+	Query(query string, args ...any) (*sql.Rows, error)
+}
+
+func loadDriveChildren(q querier, drive *entity.Drive) error {
+	var err error
+	if drive.JobRoles, err = listStrings(q, "SELECT job_role FROM drive_job_roles WHERE drive_id = ? ORDER BY position", drive.DriveID); err != nil {
+		return err
+	}
+	drive.Eligibility.Branches, err = listStrings(q, "SELECT branch FROM drive_eligible_branches WHERE drive_id = ? ORDER BY branch", drive.DriveID)
+	return err
+}
+
+func listStrings(q querier, query string, args ...any) ([]string, error) {
+	rows, err := q.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := []string{}
+	for rows.Next() {
+		var value string
+		if err := rows.Scan(&value); err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return values, nil
+}
+
+// ListDrives returns the drives matching filter.
+func (r *Repository) ListDrives(filter entity.DriveFilter) ([]*entity.Drive, error) {
+	query := `
+		SELECT
+			drive_id, institution_id, company_id, ctc, stipend, location, drive_type,
+			registration_deadline, drive_date, min_cgpa, max_backlogs, eligible_batch, status, created_by, created_at, updated_at
+		FROM drives
+		WHERE institution_id = ?`
+	args := []any{filter.InstitutionID}
+
+	if filter.CompanyID != "" {
+		query += " AND company_id = ?"
+		args = append(args, filter.CompanyID)
+	}
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+	if filter.DriveType != "" {
+		query += " AND drive_type = ?"
+		args = append(args, filter.DriveType)
+	}
+	if filter.From != nil {
+		query += " AND drive_date >= ? ORDER BY drive_date, drive_id"
+		args = append(args, *filter.From)
+	} else {
+		query += " ORDER BY drive_date DESC, drive_id"
+	}
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var drives []*entity.Drive
+	for rows.Next() {
+		drive, err := scanDrive(rows)
+		if err != nil {
+			return nil, err
+		}
+		drives = append(drives, drive)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, drive := range drives {
+		if err := loadDriveChildren(r.db, drive); err != nil {
+			return nil, err
+		}
+	}
+	return drives, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanDrive(row scanner) (*entity.Drive, error) {
+	var drive entity.Drive
+	var location sql.NullString
+	var maxBacklogs sql.NullInt64
+	if err := row.Scan(&drive.DriveID,
+		&drive.InstitutionID,
+		&drive.CompanyID,
+		&drive.CTC,
+		&drive.Stipend,
+		&location,
+		&drive.DriveType,
+		&drive.RegistrationDeadline,
+		&drive.DriveDate,
+		&drive.Eligibility.MinCGPA,
+		&maxBacklogs,
+		&drive.Eligibility.Batch,
+		&drive.Status,
+		&drive.CreatedBy,
+		&drive.CreatedAt,
+		&drive.UpdatedAt); err != nil {
+		return nil, err
+	}
+	drive.Location = location.String
+	if maxBacklogs.Valid {
+		backlogs := int(maxBacklogs.Int64)
+		drive.Eligibility.MaxBacklogs = &backlogs
+	}
+	return &drive, nil
+}
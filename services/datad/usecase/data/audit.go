@@ -0,0 +1,72 @@
+package data
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/datad/entity"
+	"errors"
+	"fmt"
+	"log"
+)
+
+// auditPageSize is how many entries VerifyAuditTrail reads at a time.
+const auditPageSize = 500
+
+// ErrAuditTampered is returned when the audit trail no longer verifies.
+var ErrAuditTampered = errors.New("audit trail has been tampered with")
+
+// GetCompanyAudit returns every recorded write to a company, oldest first,
+// including pending edits and their approval.
+func (s *Service) GetCompanyAudit(principal *auth.Principal, companyID string) ([]*entity.AuditEntry, error) {
+	if err := s.authz.Authorize(principal, common.AuditRead); err != nil {
+		return nil, err
+	}
+
+	entries, err := s.repo.ListCompanyAudit(principal.InstitutionID, companyID)
+	if err != nil {
+		log.Printf("unable to get audit history of company %s, err=%v", companyID, err)
+		return nil, err
+	}
+	return entries, nil
+}
+
+// VerifyAuditTrail walks the whole hash chain and reports the first entry
+// that was changed, removed or inserted out of band. It returns how many
+// entries were checked. Entries are only as trustworthy as the audit key: anyone
+// holding it can rewrite the chain.
+func (s *Service) VerifyAuditTrail() (int, error) {
+	var checked int
+	var lastID int64
+	var lastHash string
+	for {
+		entries, err := s.repo.ListAuditEntries(lastID, auditPageSize)
+		if err != nil {
+			log.Printf("unable to list audit entries, err=%v", err)
+			return checked, err
+		}
+		for _, entry := range entries {
+			if entry.PrevHash != lastHash {
+				return checked, fmt.Errorf("%w: entry %d does not follow entry %d", ErrAuditTampered, entry.EntryID, lastID)
+			}
+			if entry.ComputeHash(s.auditKey) != entry.Hash {
+				return checked, fmt.Errorf("%w: entry %d was modified", ErrAuditTampered, entry.EntryID)
+			}
+			lastID, lastHash = entry.EntryID, entry.Hash
+			checked++
+		}
+		if len(entries) < auditPageSize {
+			break
+		}
+	}
+
+	// The head catches entries removed from the end of the chain
+	headID, headHash, err := s.repo.GetAuditHead()
+	if err != nil {
+		log.Printf("unable to get audit head, err=%v", err)
+		return checked, err
+	}
+	if headID != lastID || headHash != lastHash {
+		return checked, fmt.Errorf("%w: chain ends at entry %d but entry %d was written last", ErrAuditTampered, lastID, headID)
+	}
+	return checked, nil
+}
@@ -1,6 +1,9 @@
 package data
 
-import "backend/services/datad/entity"
+import (
+	"backend/pkg/auth"
+	"backend/services/datad/entity"
+)
 
 type Repository interface {
 	Writer
@@ -8,40 +11,40 @@ type Repository interface {
 }
 
 type Writer interface {
-	CreateCompany(companyData *entity.CompanyData) error
-	UpdateCompany(companyData *entity.CompanyData) error
-	SetAwaitingApproval(companyID string, isApproved bool) (*entity.CompanyData, error)
+	CreateCompany(companyData *entity.CompanyData, actorID string) error
+	UpdateCompany(companyData *entity.CompanyData, actorID string) error
+	SetAwaitingApproval(institutionID, companyID string, isApproved bool, actorID string) (*entity.CompanyData, error)
 }
 
 type Reader interface {
-	GetCompany(id string) (*entity.CompanyData, error)
-	GetAwaitingApproval() ([]*entity.CompanyData, error)
+	GetCompany(institutionID, id string) (*entity.CompanyData, error)
+	GetAwaitingApproval(institutionID string) ([]*entity.CompanyData, error)
+	ListCompanyAudit(institutionID, companyID string) ([]*entity.AuditEntry, error)
+	ListAuditEntries(afterID int64, limit int) ([]*entity.AuditEntry, error)
+	GetAuditHead() (entryID int64, hash string, err error)
 }
 
 type Usecase interface {
-	CreateCompany(jwt,
+	CreateCompany(principal *auth.Principal,
 		companyName,
 		CompanyAddress,
-		Drive,
-		TypeOfDrive,
 		FollowUp,
 		Remarks,
 		ContactDetails,
 		HRDetails string,
 		isContacted bool) (string, error)
-	GetCompany(jwtString, id string) (*entity.CompanyData, error)
-	GetCompanyByName(jwtString, name string) (*entity.CompanyData, error)
-	UpdateCompany(jwt,
+	GetCompany(principal *auth.Principal, id string) (*entity.CompanyData, error)
+	GetCompanyByName(principal *auth.Principal, name string) (*entity.CompanyData, error)
+	UpdateCompany(principal *auth.Principal,
 		companyID,
 		companyName,
 		CompanyAddress,
-		Drive,
-		TypeOfDrive,
 		FollowUp,
 		Remarks,
 		ContactDetails,
 		HRDetails string,
 		isContacted bool) (*entity.CompanyData, error)
-	GetAwaitingApproval() ([]*entity.CompanyData, error)
-	SetAwaitingApproval(jwtString, companyID string, isApproved bool) (*entity.CompanyData, error)
+	GetAwaitingApproval(principal *auth.Principal) ([]*entity.CompanyData, error)
+	SetAwaitingApproval(principal *auth.Principal, companyID string, isApproved bool) (*entity.CompanyData, error)
+	GetCompanyAudit(principal *auth.Principal, companyID string) ([]*entity.AuditEntry, error)
 }
@@ -1,109 +1,41 @@
 package data
 
 import (
+	"backend/pkg/auth"
 	"backend/pkg/common"
 	"backend/services/datad/entity"
-	"fmt"
 	"log"
-
-	"github.com/golang-jwt/jwt/v5"
 )
 
 type Service struct {
-	repo      Repository
-	JWTSecret string
+	repo  Repository
+	authz *auth.Authorizer
+	// auditKey verifies the audit trail's hash chain
+	auditKey []byte
 }
 
-func NewService(repo Repository, jwtSecret string) *Service {
+func NewService(repo Repository, authz *auth.Authorizer, auditKey []byte) *Service {
 	return &Service{
-		repo:      repo,
-		JWTSecret: jwtSecret,
+		repo:     repo,
+		authz:    authz,
+		auditKey: auditKey,
 	}
 }
 
-func (s *Service) validateJWTAndRole(jwtString string) error {
-	// Skip JWT validation if no JWT secret is set or jwt string is empty
-	if s.JWTSecret == "" || jwtString == "" {
-		log.Printf("Skipping JWT validation: secret empty=%v, token empty=%v", s.JWTSecret == "", jwtString == "")
-		return nil
-	}
-
-	// Validate JWT and check if the user has the required role
-	token, err := jwt.Parse(jwtString, func(token *jwt.Token) (interface{}, error) {
-		// Validate the alg is what you expect:
-		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-		}
-		return []byte(s.JWTSecret), nil
-	})
-
-	if err != nil {
-		log.Printf("unable to parse JWT, err=%v", err)
-		return fmt.Errorf("invalid token: %w", err)
-	}
-
-	// Check if token is nil
-	if token == nil {
-		return fmt.Errorf("invalid token: token is nil")
-	}
-
-	// Try to extract claims
-	claims, ok := token.Claims.(jwt.MapClaims)
-	if !ok || !token.Valid {
-		return fmt.Errorf("invalid token: failed to process claims")
-	}
-
-	// Check for required role
-	roleClaim, ok := claims["role"]
-	if !ok {
-		return fmt.Errorf("invalid token: role claim missing")
-	}
-
-	role, ok := roleClaim.(string)
-	if !ok {
-		return fmt.Errorf("invalid token: role claim type invalid")
-	}
-
-	// Check if ValidRolesToCreateData is nil or empty
-	if common.ValidRolesToCreateData == nil || len(common.ValidRolesToCreateData) == 0 {
-		return nil
-	}
-
-	// Check if the user's role is in the list of valid roles
-	isAllowed := false
-	for _, allowedRole := range common.ValidRolesToCreateData {
-		if role == allowedRole {
-			isAllowed = true
-			break
-		}
-	}
-
-	if !isAllowed {
-		log.Printf("user role '%s' does not have permission to create data", role)
-		return fmt.Errorf("permission denied: insufficient role")
-	}
-
-	return nil
-}
-
-func (s *Service) CreateCompany(jwtString string,
+func (s *Service) CreateCompany(principal *auth.Principal,
 	CompanyName,
 	CompanyAddress,
-	Drive,
-	TypeOfDrive,
 	FollowUp,
 	Remarks,
 	ContactDetails,
 	HRDetails string,
 	IsContacted bool) (string, error) {
-	// if err := s.validateJWTAndRole(jwtString); err != nil {
-	// 	return "", err
-	// }
+	if err := s.authz.Authorize(principal, common.CompanyCreate); err != nil {
+		return "", err
+	}
 
 	companyData, err := entity.NewCompany(CompanyName,
 		CompanyAddress,
-		Drive,
-		TypeOfDrive,
 		FollowUp,
 		Remarks,
 		ContactDetails,
@@ -113,8 +45,10 @@ func (s *Service) CreateCompany(jwtString string,
 		log.Printf("unable to create company, err=%v", err)
 		return "", err
 	}
+	companyData.InstitutionID = principal.InstitutionID
+	companyData.OwnerID = principal.UserID
 
-	err = s.repo.CreateCompany(companyData)
+	err = s.repo.CreateCompany(companyData, principal.UserID)
 	if err != nil {
 		log.Printf("unable to create company in repo, err=%v", err)
 		return "", err
@@ -123,12 +57,12 @@ func (s *Service) CreateCompany(jwtString string,
 	return companyData.CompanyID, nil
 }
 
-func (s *Service) GetCompany(jwtString string, id string) (*entity.CompanyData, error) {
-	// if err := s.validateJWTAndRole(jwtString); err != nil {
-	// 	return nil, err
-	// }
+func (s *Service) GetCompany(principal *auth.Principal, id string) (*entity.CompanyData, error) {
+	if err := s.authz.Authorize(principal, common.CompanyRead); err != nil {
+		return nil, err
+	}
 
-	companyData, err := s.repo.GetCompany(id)
+	companyData, err := s.repo.GetCompany(principal.InstitutionID, id)
 	if err != nil {
 		log.Printf("unable to get company, err=%v", err)
 		return nil, err
@@ -137,12 +71,12 @@ func (s *Service) GetCompany(jwtString string, id string) (*entity.CompanyData,
 	return companyData, nil
 }
 
-func (s *Service) GetCompanyByName(jwtString string, name string) (*entity.CompanyData, error) {
-	// if err := s.validateJWTAndRole(jwtString); err != nil {
-	// 	return nil, err
-	// }
+func (s *Service) GetCompanyByName(principal *auth.Principal, name string) (*entity.CompanyData, error) {
+	if err := s.authz.Authorize(principal, common.CompanyRead); err != nil {
+		return nil, err
+	}
 
-	companyData, err := s.repo.GetCompany(name)
+	companyData, err := s.repo.GetCompany(principal.InstitutionID, name)
 	if err != nil {
 		log.Printf("unable to get company, err=%v", err)
 		return nil, err
@@ -151,25 +85,21 @@ func (s *Service) GetCompanyByName(jwtString string, name string) (*entity.Compa
 	return companyData, nil
 }
 
-func (s *Service) UpdateCompany(jwtString string,
+func (s *Service) UpdateCompany(principal *auth.Principal,
 	CompanyID,
 	CompanyName,
 	CompanyAddress,
-	Drive,
-	TypeOfDrive,
 	FollowUp,
 	Remarks,
 	ContactDetails,
 	HRDetails string,
 	IsContacted bool) (*entity.CompanyData, error) {
-	// if err := s.validateJWTAndRole(jwtString); err != nil {
-	// 	return "", err
-	// }
+	if err := s.authz.Authorize(principal, common.CompanyUpdate); err != nil {
+		return nil, err
+	}
 
 	companyData, err := entity.NewCompany(CompanyName,
 		CompanyAddress,
-		Drive,
-		TypeOfDrive,
 		FollowUp,
 		Remarks,
 		ContactDetails,
@@ -179,8 +109,10 @@ func (s *Service) UpdateCompany(jwtString string,
 		log.Printf("unable to create company, err=%v", err)
 		return nil, err
 	}
+	companyData.CompanyID = CompanyID
+	companyData.InstitutionID = principal.InstitutionID
 
-	err = s.repo.UpdateCompany(companyData)
+	err = s.repo.UpdateCompany(companyData, principal.UserID)
 	if err != nil {
 		log.Printf("unable to create company in repo, err=%v", err)
 		return nil, err
@@ -189,18 +121,19 @@ func (s *Service) UpdateCompany(jwtString string,
 	return companyData, nil
 }
 
-// TODO check jwt token before calling this function
-func (s *Service) GetAwaitingApproval() ([]*entity.CompanyData, error) {
-	return s.repo.GetAwaitingApproval()
+func (s *Service) GetAwaitingApproval(principal *auth.Principal) ([]*entity.CompanyData, error) {
+	if err := s.authz.Authorize(principal, common.CompanyApprove); err != nil {
+		return nil, err
+	}
+	return s.repo.GetAwaitingApproval(principal.InstitutionID)
 }
 
-func (s *Service) SetAwaitingApproval(jwtString, companyID string, isApproved bool) (*entity.CompanyData, error) {
-	// TODO: Add JWT validation/role check if necessary
-	// if err := s.validateJWTAndRole(jwtString); err != nil { // Add appropriate role check later
-	// 	return nil, err
-	// }
+func (s *Service) SetAwaitingApproval(principal *auth.Principal, companyID string, isApproved bool) (*entity.CompanyData, error) {
+	if err := s.authz.Authorize(principal, common.CompanyApprove); err != nil {
+		return nil, err
+	}
 
-	companyData, err := s.repo.SetAwaitingApproval(companyID, isApproved)
+	companyData, err := s.repo.SetAwaitingApproval(principal.InstitutionID, companyID, isApproved, principal.UserID)
 	if err != nil {
 		log.Printf("unable to set awaiting approval status for company %s, err=%v", companyID, err)
 		return nil, err
@@ -0,0 +1,34 @@
+package drive
+
+import (
+	"backend/pkg/auth"
+	"backend/services/datad/entity"
+)
+
+type Repository interface {
+	Writer
+	Reader
+}
+
+type Writer interface {
+	CreateDrive(drive *entity.Drive, actorID string) error
+	UpdateDrive(drive *entity.Drive, actorID string) error
+	DeleteDrive(institutionID, driveID, actorID string) error
+}
+
+type Reader interface {
+	GetCompany(institutionID, id string) (*entity.CompanyData, error)
+	GetDrive(institutionID, driveID string) (*entity.Drive, error)
+	ListDrives(filter entity.DriveFilter) ([]*entity.Drive, error)
+	CountDriveApplications(institutionID, driveID string) (int, error)
+}
+
+type Usecase interface {
+	CreateDrive(principal *auth.Principal, companyID string, details entity.DriveDetails) (*entity.Drive, error)
+	GetDrive(principal *auth.Principal, driveID string) (*entity.Drive, error)
+	ListDrives(principal *auth.Principal, filter entity.DriveFilter) ([]*entity.Drive, error)
+	ListUpcomingDrives(principal *auth.Principal) ([]*entity.Drive, error)
+	UpdateDrive(principal *auth.Principal, driveID string, details entity.DriveDetails) (*entity.Drive, error)
+	SetDriveStatus(principal *auth.Principal, driveID, status string) (*entity.Drive, error)
+	DeleteDrive(principal *auth.Principal, driveID string) error
+}
@@ -0,0 +1,148 @@
+package drive
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/datad/entity"
+	"log"
+	"time"
+)
+
+type Service struct {
+	repo  Repository
+	authz *auth.Authorizer
+}
+
+func NewService(repo Repository, authz *auth.Authorizer) *Service {
+	return &Service{
+		repo:  repo,
+		authz: authz,
+	}
+}
+
+// CreateDrive schedules a drive of a company of the caller's institution.
+func (s *Service) CreateDrive(principal *auth.Principal, companyID string, details entity.DriveDetails) (*entity.Drive, error) {
+	if err := s.authz.Authorize(principal, common.DriveManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetCompany(principal.InstitutionID, companyID); err != nil {
+		log.Printf("unable to get company %s, err=%v", companyID, err)
+		return nil, err
+	}
+	drive, err := entity.NewDrive(principal.InstitutionID, companyID, principal.UserID, details)
+	if err != nil {
+		log.Printf("unable to create drive entity, err=%v", err)
+		return nil, err
+	}
+
+	if err := s.repo.CreateDrive(drive, principal.UserID); err != nil {
+		log.Printf("unable to create drive in repository, err=%v", err)
+		return nil, err
+	}
+	return drive, nil
+}
+
+func (s *Service) GetDrive(principal *auth.Principal, driveID string) (*entity.Drive, error) {
+	if err := s.authz.Authorize(principal, common.DriveRead); err != nil {
+		return nil, err
+	}
+
+	drive, err := s.repo.GetDrive(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to get drive %s, err=%v", driveID, err)
+		return nil, err
+	}
+	return drive, nil
+}
+
+func (s *Service) ListDrives(principal *auth.Principal, filter entity.DriveFilter) ([]*entity.Drive, error) {
+	if err := s.authz.Authorize(principal, common.DriveRead); err != nil {
+		return nil, err
+	}
+	filter.InstitutionID = principal.InstitutionID
+
+	drives, err := s.repo.ListDrives(filter)
+	if err != nil {
+		log.Printf("unable to list drives, err=%v", err)
+		return nil, err
+	}
+	return drives, nil
+}
+
+// ListUpcomingDrives returns the scheduled drives that have not happened
+// yet, soonest first.
+func (s *Service) ListUpcomingDrives(principal *auth.Principal) ([]*entity.Drive, error) {
+	now := time.Now()
+	return s.ListDrives(principal, entity.DriveFilter{
+		Status: entity.DriveScheduled,
+		From:   &now,
+	})
+}
+
+func (s *Service) UpdateDrive(principal *auth.Principal, driveID string, details entity.DriveDetails) (*entity.Drive, error) {
+	if err := s.authz.Authorize(principal, common.DriveManage); err != nil {
+		return nil, err
+	}
+
+	drive, err := s.repo.GetDrive(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to get drive %s, err=%v", driveID, err)
+		return nil, err
+	}
+	if err := drive.Update(details); err != nil {
+		log.Printf("unable to update drive entity, err=%v", err)
+		return nil, err
+	}
+
+	if err := s.repo.UpdateDrive(drive, principal.UserID); err != nil {
+		log.Printf("unable to update drive %s in repository, err=%v", driveID, err)
+		return nil, err
+	}
+	return drive, nil
+}
+
+// SetDriveStatus marks a scheduled drive as completed or cancelled.
+func (s *Service) SetDriveStatus(principal *auth.Principal, driveID, status string) (*entity.Drive, error) {
+	if err := s.authz.Authorize(principal, common.DriveManage); err != nil {
+		return nil, err
+	}
+
+	drive, err := s.repo.GetDrive(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to get drive %s, err=%v", driveID, err)
+		return nil, err
+	}
+	if err := drive.SetStatus(status); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.UpdateDrive(drive, principal.UserID); err != nil {
+		log.Printf("unable to update drive %s in repository, err=%v", driveID, err)
+		return nil, err
+	}
+	return drive, nil
+}
+
+// DeleteDrive removes a drive nobody applied to. Drives with applications
+// are cancelled instead, so the students keep their history.
+func (s *Service) DeleteDrive(principal *auth.Principal, driveID string) error {
+	if err := s.authz.Authorize(principal, common.DriveManage); err != nil {
+		return err
+	}
+
+	applications, err := s.repo.CountDriveApplications(principal.InstitutionID, driveID)
+	if err != nil {
+		log.Printf("unable to count applications of drive %s, err=%v", driveID, err)
+		return err
+	}
+	if applications > 0 {
+		return entity.ErrDriveHasApplications
+	}
+
+	if err := s.repo.DeleteDrive(principal.InstitutionID, driveID, principal.UserID); err != nil {
+		log.Printf("unable to delete drive %s, err=%v", driveID, err)
+		return err
+	}
+	return nil
+}
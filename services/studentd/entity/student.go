@@ -0,0 +1,214 @@
+package entity
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Verification statuses of a student profile
+const (
+	VerificationPending  = "pending"
+	VerificationVerified = "verified"
+	VerificationRejected = "rejected"
+)
+
+const (
+	maxSkills      = 50
+	maxSkillLength = 64
+)
+
+var (
+	// ErrInvalidStudent is wrapped by every validation error of a profile.
+	ErrInvalidStudent = errors.New("invalid student profile")
+	// ErrStudentChanged is returned when a profile was written since it was read.
+	ErrStudentChanged = errors.New("student profile was changed since it was read")
+)
+
+var (
+	emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	phoneRegex = regexp.MustCompile(`^\+?[0-9][0-9 -]{6,18}[0-9]$`)
+)
+
+// Student is the placement profile of a user with the student role. The
+// academic record is only trusted for eligibility once an officer has
+// verified it.
+type Student struct {
+	UserID             string
+	InstitutionID      string
+	RollNumber         string
+	Branch             string
+	Batch              int
+	CGPA               float64
+	Backlogs           int
+	Phone              string
+	PersonalEmail      string
+	Skills             []string
+	VerificationStatus string
+	VerificationNote   string
+	VerifiedBy         string
+	VerifiedAt         *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+	// Version counts the writes to the profile, detail edits and
+	// verifications alike
+	Version int
+}
+
+// StudentDetails are the fields students fill in themselves.
+type StudentDetails struct {
+	RollNumber    string
+	Branch        string
+	Batch         int
+	CGPA          float64
+	Backlogs      int
+	Phone         string
+	PersonalEmail string
+	Skills        []string
+}
+
+func NewStudent(userID, institutionID string, details StudentDetails) (*Student, error) {
+	now := time.Now()
+	student := &Student{
+		UserID:             userID,
+		InstitutionID:      institutionID,
+		VerificationStatus: VerificationPending,
+		CreatedAt:          now,
+		UpdatedAt:          now,
+		Version:            1,
+	}
+	student.apply(details)
+
+	if err := student.validate(); err != nil {
+		return nil, err
+	}
+
+	return student, nil
+}
+
+// Update replaces the details, keeping the old ones if the new ones are
+// invalid. Changes to the academic record need to be verified again;
+// contact details and skills do not.
+func (s *Student) Update(details StudentDetails) error {
+	updated := *s
+	updated.apply(details)
+	if err := updated.validate(); err != nil {
+		return err
+	}
+
+	if updated.RollNumber != s.RollNumber || updated.Branch != s.Branch || updated.Batch != s.Batch ||
+		updated.CGPA != s.CGPA || updated.Backlogs != s.Backlogs {
+		updated.VerificationStatus = VerificationPending
+		updated.VerificationNote = ""
+		updated.VerifiedBy = ""
+		updated.VerifiedAt = nil
+	}
+	updated.UpdatedAt = time.Now()
+	*s = updated
+	return nil
+}
+
+// Verify records an officer's decision on the academic record. Rejections
+// need a note telling the student what to fix.
+func (s *Student) Verify(officerID string, approved bool, note string) error {
+	note = strings.TrimSpace(note)
+	status := VerificationVerified
+	if !approved {
+		if note == "" {
+			return fmt.Errorf("%w: a rejection needs a note", ErrInvalidStudent)
+		}
+		status = VerificationRejected
+	}
+
+	now := time.Now()
+	s.VerificationStatus = status
+	s.VerificationNote = note
+	s.VerifiedBy = officerID
+	s.VerifiedAt = &now
+	return nil
+}
+
+func (s *Student) IsVerified() bool {
+	return s.VerificationStatus == VerificationVerified
+}
+
+func (s *Student) apply(details StudentDetails) {
+	s.RollNumber = strings.ToUpper(strings.TrimSpace(details.RollNumber))
+	s.Branch = strings.TrimSpace(details.Branch)
+	s.Batch = details.Batch
+	// The database keeps two decimals
+	s.CGPA = math.Round(details.CGPA*100) / 100
+	s.Backlogs = details.Backlogs
+	s.Phone = strings.TrimSpace(details.Phone)
+	s.PersonalEmail = strings.TrimSpace(details.PersonalEmail)
+	s.Skills = normalizeSkills(details.Skills)
+}
+
+// normalizeSkills trims skills and drops empty and repeated ones, comparing
+// case-insensitively like the database does.
+func normalizeSkills(skills []string) []string {
+	normalized := make([]string, 0, len(skills))
+	seen := make(map[string]bool, len(skills))
+	for _, skill := range skills {
+		skill = strings.TrimSpace(skill)
+		key := strings.ToLower(skill)
+		if skill == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		normalized = append(normalized, skill)
+	}
+	return normalized
+}
+
+func (s *Student) validate() error {
+	if s.RollNumber == "" {
+		return fmt.Errorf("%w: roll number cannot be empty", ErrInvalidStudent)
+	} else if len(s.RollNumber) > 32 {
+		return fmt.Errorf("%w: roll number cannot be longer than 32 characters", ErrInvalidStudent)
+	}
+	if s.Branch == "" {
+		return fmt.Errorf("%w: branch cannot be empty", ErrInvalidStudent)
+	} else if len(s.Branch) > 100 {
+		return fmt.Errorf("%w: branch cannot be longer than 100 characters", ErrInvalidStudent)
+	}
+	if s.Batch < 2000 || s.Batch > 2100 {
+		return fmt.Errorf("%w: batch must be a graduation year", ErrInvalidStudent)
+	}
+	if s.CGPA < 0 || s.CGPA > 10 {
+		return fmt.Errorf("%w: cgpa must be between 0 and 10", ErrInvalidStudent)
+	}
+	if s.Backlogs < 0 {
+		return fmt.Errorf("%w: backlogs cannot be negative", ErrInvalidStudent)
+	}
+	if s.Phone != "" && !phoneRegex.MatchString(s.Phone) {
+		return fmt.Errorf("%w: invalid phone number", ErrInvalidStudent)
+	}
+	if s.PersonalEmail != "" && !emailRegex.MatchString(s.PersonalEmail) {
+		return fmt.Errorf("%w: invalid personal email format", ErrInvalidStudent)
+	}
+	if len(s.Skills) > maxSkills {
+		return fmt.Errorf("%w: at most %d skills are allowed", ErrInvalidStudent, maxSkills)
+	}
+	for _, skill := range s.Skills {
+		if len(skill) > maxSkillLength {
+			return fmt.Errorf("%w: skill %q is longer than %d characters", ErrInvalidStudent, skill, maxSkillLength)
+		}
+	}
+	return nil
+}
+
+// StudentFilter selects a page of students ordered by roll number. After
+// is the roll number of the last student of the previous page.
+type StudentFilter struct {
+	InstitutionID      string
+	Branch             string
+	Batch              int
+	VerificationStatus string
+	Skill              string
+	After              string
+	Limit              int
+}
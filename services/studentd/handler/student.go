@@ -0,0 +1,248 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/studentd/entity"
+	"backend/services/studentd/presenter"
+	"backend/services/studentd/usecase/student"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// errorStatus maps usecase errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, student.ErrStudentNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, student.ErrRollNumberTaken), errors.Is(err, entity.ErrStudentChanged):
+		return http.StatusConflict
+	case errors.Is(err, entity.ErrInvalidStudent), errors.Is(err, student.ErrInvalidCursor):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+func toStudentResponse(s *entity.Student) presenter.StudentResponse {
+	resp := presenter.StudentResponse{
+		UserID:             s.UserID,
+		RollNumber:         s.RollNumber,
+		Branch:             s.Branch,
+		Batch:              s.Batch,
+		CGPA:               s.CGPA,
+		Backlogs:           s.Backlogs,
+		Phone:              s.Phone,
+		PersonalEmail:      s.PersonalEmail,
+		Skills:             s.Skills,
+		VerificationStatus: s.VerificationStatus,
+		VerificationNote:   s.VerificationNote,
+		VerifiedBy:         s.VerifiedBy,
+		CreatedAt:          s.CreatedAt.Unix(),
+		UpdatedAt:          s.UpdatedAt.Unix(),
+		Version:            s.Version,
+	}
+	if s.VerifiedAt != nil {
+		resp.VerifiedAt = s.VerifiedAt.Unix()
+	}
+	return resp
+}
+
+func writeStudent(w http.ResponseWriter, s *entity.Student) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(toStudentResponse(s)); err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func getOwnProfile(service student.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		found, err := service.GetOwnProfile(auth.FromContext(r.Context()))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to get student profile, err=%v", err), errorStatus(err))
+			return
+		}
+		writeStudent(w, found)
+	}
+}
+
+func saveOwnProfile(service student.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.StudentRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		saved, err := service.SaveOwnProfile(auth.FromContext(r.Context()), entity.StudentDetails{
+			RollNumber:    req.RollNumber,
+			Branch:        req.Branch,
+			Batch:         req.Batch,
+			CGPA:          req.CGPA,
+			Backlogs:      req.Backlogs,
+			Phone:         req.Phone,
+			PersonalEmail: req.PersonalEmail,
+			Skills:        req.Skills,
+		})
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to save student profile, err=%v", err), errorStatus(err))
+			return
+		}
+		writeStudent(w, saved)
+	}
+}
+
+// listStudents serves the student directory of officers:
+// GET /v1/students?branch=&batch=&status=&skill=&cursor=&limit=
+func listStudents(service student.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter, err := parseStudentFilter(query)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to parse query, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		students, next, err := service.ListStudents(auth.FromContext(r.Context()), filter, query.Get("cursor"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list students, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := presenter.ListStudentsResponse{
+			Students:   make([]presenter.StudentResponse, 0, len(students)),
+			NextCursor: next,
+		}
+		for _, s := range students {
+			resp.Students = append(resp.Students, toStudentResponse(s))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func parseStudentFilter(query url.Values) (entity.StudentFilter, error) {
+	filter := entity.StudentFilter{
+		Branch:             query.Get("branch"),
+		VerificationStatus: query.Get("status"),
+		Skill:              query.Get("skill"),
+	}
+
+	switch filter.VerificationStatus {
+	case "", entity.VerificationPending, entity.VerificationVerified, entity.VerificationRejected:
+	default:
+		return filter, fmt.Errorf("unknown status %q", filter.VerificationStatus)
+	}
+	if v := query.Get("batch"); v != "" {
+		batch, err := strconv.Atoi(v)
+		if err != nil || batch <= 0 {
+			return filter, fmt.Errorf("invalid batch %q", v)
+		}
+		filter.Batch = batch
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", v)
+		}
+		filter.Limit = limit
+	}
+	return filter, nil
+}
+
+func getStudent(service student.Usecase, userID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		found, err := service.GetStudent(auth.FromContext(r.Context()), userID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to get student, err=%v", err), errorStatus(err))
+			return
+		}
+		writeStudent(w, found)
+	}
+}
+
+func verifyStudent(service student.Usecase, userID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.VerificationRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+		if req.Status != entity.VerificationVerified && req.Status != entity.VerificationRejected {
+			http.Error(w, fmt.Sprintf("status must be %q or %q", entity.VerificationVerified, entity.VerificationRejected), http.StatusBadRequest)
+			return
+		}
+		if req.Version <= 0 {
+			http.Error(w, "version of the reviewed profile is required", http.StatusBadRequest)
+			return
+		}
+
+		verified, err := service.VerifyStudent(auth.FromContext(r.Context()), userID, req.Version, req.Status == entity.VerificationVerified, req.Note)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to verify student, err=%v", err), errorStatus(err))
+			return
+		}
+		writeStudent(w, verified)
+	}
+}
+
+// Register Student Routes
+func RegisterStudentHandlers(service student.Usecase, authn *auth.Authenticator) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.Handle(pattern, authn.Middleware(handler))
+	}
+
+	handle("/v1/me/student", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			getOwnProfile(service)(w, r)
+		case http.MethodPut:
+			saveOwnProfile(service)(w, r)
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	handle("/v1/students", listStudents(service)) // GET
+	handle("/v1/students/", func(w http.ResponseWriter, r *http.Request) {
+		// Split /v1/students/{id}/{action}
+		id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/students/"), "/"), "/")
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, fmt.Sprintf("invalid student ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			getStudent(service, id)(w, r) //v1/students/{id} // GET
+		case action == "verification":
+			verifyStudent(service, id)(w, r) //v1/students/{id}/verification // PUT {status, note, version}
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+}
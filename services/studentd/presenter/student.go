@@ -0,0 +1,44 @@
+package presenter
+
+type StudentRequest struct {
+	RollNumber    string   `json:"roll_number"`
+	Branch        string   `json:"branch"`
+	Batch         int      `json:"batch"`
+	CGPA          float64  `json:"cgpa"`
+	Backlogs      int      `json:"backlogs"`
+	Phone         string   `json:"phone"`
+	PersonalEmail string   `json:"personal_email"`
+	Skills        []string `json:"skills"`
+}
+
+type StudentResponse struct {
+	UserID             string   `json:"user_id"`
+	RollNumber         string   `json:"roll_number"`
+	Branch             string   `json:"branch"`
+	Batch              int      `json:"batch"`
+	CGPA               float64  `json:"cgpa"`
+	Backlogs           int      `json:"backlogs"`
+	Phone              string   `json:"phone,omitempty"`
+	PersonalEmail      string   `json:"personal_email,omitempty"`
+	Skills             []string `json:"skills"`
+	VerificationStatus string   `json:"verification_status"`
+	VerificationNote   string   `json:"verification_note,omitempty"`
+	VerifiedBy         string   `json:"verified_by,omitempty"`
+	VerifiedAt         int64    `json:"verified_at,omitempty"`
+	CreatedAt          int64    `json:"created_at"`
+	UpdatedAt          int64    `json:"updated_at"`
+	Version            int      `json:"version"`
+}
+
+type ListStudentsResponse struct {
+	Students   []StudentResponse `json:"students"`
+	NextCursor string            `json:"next_cursor,omitempty"`
+}
+
+// VerificationRequest carries an officer's decision, "verified" or
+// "rejected", on the profile version they reviewed.
+type VerificationRequest struct {
+	Status  string `json:"status"`
+	Note    string `json:"note"`
+	Version int    `json:"version"`
+}
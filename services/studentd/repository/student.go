@@ -0,0 +1,266 @@
+package repository
+
+import (
+	"backend/services/studentd/entity"
+	"database/sql"
+)
+
+type Repository struct {
+	db *sql.DB
+}
+
+func NewStudentRepository(db *sql.DB) *Repository {
+	return &Repository{
+		db: db,
+	}
+}
+
+const studentColumns = `
+	user_id, institution_id, roll_number, branch, batch, cgpa, backlogs, phone, personal_email,
+	verification_status, verification_note, verified_by, verified_at, created_at, updated_at, version`
+
+// CreateStudent stores a new profile with its skills.
+func (r *Repository) CreateStudent(student *entity.Student) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO students (`+studentColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`, student.UserID, student.InstitutionID, student.RollNumber, student.Branch, student.Batch, student.CGPA, student.Backlogs,
+		nullString(student.Phone), nullString(student.PersonalEmail), student.VerificationStatus, nullString(student.VerificationNote),
+		nullString(student.VerifiedBy), student.VerifiedAt, student.CreatedAt, student.UpdatedAt, student.Version)
+	if err != nil {
+		return err
+	}
+	if err := insertSkills(tx, student.UserID, student.Skills); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// UpdateStudent saves every field of a profile and replaces its skills. It
+// returns entity.ErrStudentChanged if the profile was written since
+// student.Version was read.
+func (r *Repository) UpdateStudent(student *entity.Student) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec(`
+		UPDATE students SET
+			roll_number = ?, branch = ?, batch = ?, cgpa = ?, backlogs = ?, phone = ?, personal_email = ?,
+			verification_status = ?, verification_note = ?, verified_by = ?, verified_at = ?, updated_at = ?,
+			version = version + 1
+		WHERE user_id = ? AND institution_id = ? AND version = ?;
+	`, student.RollNumber, student.Branch, student.Batch, student.CGPA, student.Backlogs, nullString(student.Phone),
+		nullString(student.PersonalEmail), student.VerificationStatus, nullString(student.VerificationNote),
+		nullString(student.VerifiedBy), student.VerifiedAt, student.UpdatedAt, student.UserID, student.InstitutionID, student.Version)
+	if err != nil {
+		return err
+	}
+	if err := checkVersionBumped(result); err != nil {
+		return err
+	}
+
+	if _, err := tx.Exec("DELETE FROM student_skills WHERE user_id = ?;", student.UserID); err != nil {
+		return err
+	}
+	if err := insertSkills(tx, student.UserID, student.Skills); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	student.Version++
+	return nil
+}
+
+func insertSkills(tx *sql.Tx, userID string, skills []string) error {
+	for _, skill := range skills {
+		if _, err := tx.Exec("INSERT INTO student_skills (user_id, skill) VALUES (?, ?);", userID, skill); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SetVerification records an officer's decision without touching the
+// details, so it does not count as an edit of the profile. Like
+// UpdateStudent it returns entity.ErrStudentChanged if the profile was
+// written since student.Version was read, so the decision never applies to
+// details the officer has not seen.
+func (r *Repository) SetVerification(student *entity.Student) error {
+	result, err := r.db.Exec(`
+		UPDATE students SET verification_status = ?, verification_note = ?, verified_by = ?, verified_at = ?,
+			version = version + 1
+		WHERE user_id = ? AND institution_id = ? AND version = ?;
+	`, student.VerificationStatus, nullString(student.VerificationNote), nullString(student.VerifiedBy), student.VerifiedAt,
+		student.UserID, student.InstitutionID, student.Version)
+	if err != nil {
+		return err
+	}
+	if err := checkVersionBumped(result); err != nil {
+		return err
+	}
+	student.Version++
+	return nil
+}
+
+// checkVersionBumped reports entity.ErrStudentChanged when a versioned
+// write matched no row.
+func checkVersionBumped(result sql.Result) error {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return entity.ErrStudentChanged
+	}
+	return nil
+}
+
+func (r *Repository) GetStudent(institutionID, userID string) (*entity.Student, error) {
+	return r.getStudent("user_id", institutionID, userID)
+}
+
+func (r *Repository) GetStudentByRollNumber(institutionID, rollNumber string) (*entity.Student, error) {
+	return r.getStudent("roll_number", institutionID, rollNumber)
+}
+
+func (r *Repository) getStudent(column, institutionID, value string) (*entity.Student, error) {
+	student, err := scanStudent(r.db.QueryRow(`
+		SELECT `+studentColumns+`
+		FROM students WHERE institution_id = ? AND `+column+` = ?;
+	`, institutionID, value))
+	if err != nil {
+		return nil, err
+	}
+	if student.Skills, err = r.listSkills(student.UserID); err != nil {
+		return nil, err
+	}
+	return student, nil
+}
+
+func (r *Repository) listSkills(userID string) ([]string, error) {
+	rows, err := r.db.Query("SELECT skill FROM student_skills WHERE user_id = ? ORDER BY skill;", userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	skills := []string{}
+	for rows.Next() {
+		var skill string
+		if err := rows.Scan(&skill); err != nil {
+			return nil, err
+		}
+		skills = append(skills, skill)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return skills, nil
+}
+
+// ListStudents returns the students matching filter, by roll number.
+func (r *Repository) ListStudents(filter entity.StudentFilter) ([]*entity.Student, error) {
+	query := `
+		SELECT ` + studentColumns + `
+		FROM students WHERE institution_id = ?`
+	args := []any{filter.InstitutionID}
+
+	if filter.Branch != "" {
+		query += " AND branch = ?"
+		args = append(args, filter.Branch)
+	}
+	if filter.Batch != 0 {
+		query += " AND batch = ?"
+		args = append(args, filter.Batch)
+	}
+	if filter.VerificationStatus != "" {
+		query += " AND verification_status = ?"
+		args = append(args, filter.VerificationStatus)
+	}
+	if filter.Skill != "" {
+		query += " AND user_id IN (SELECT user_id FROM student_skills WHERE skill = ?)"
+		args = append(args, filter.Skill)
+	}
+	if filter.After != "" {
+		query += " AND roll_number > ?"
+		args = append(args, filter.After)
+	}
+	query += " ORDER BY roll_number LIMIT ?;"
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var students []*entity.Student
+	for rows.Next() {
+		student, err := scanStudent(rows)
+		if err != nil {
+			return nil, err
+		}
+		students = append(students, student)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	for _, student := range students {
+		if student.Skills, err = r.listSkills(student.UserID); err != nil {
+			return nil, err
+		}
+	}
+	return students, nil
+}
+
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanStudent(row scanner) (*entity.Student, error) {
+	var student entity.Student
+	var phone, personalEmail, verificationNote, verifiedBy sql.NullString
+	var verifiedAt sql.NullTime
+	if err := row.Scan(&student.UserID,
+		&student.InstitutionID,
+		&student.RollNumber,
+		&student.Branch,
+		&student.Batch,
+		&student.CGPA,
+		&student.Backlogs,
+		&phone,
+		&personalEmail,
+		&student.VerificationStatus,
+		&verificationNote,
+		&verifiedBy,
+		&verifiedAt,
+		&student.CreatedAt,
+		&student.UpdatedAt,
+		&student.Version); err != nil {
+		return nil, err
+	}
+	student.Phone = phone.String
+	student.PersonalEmail = personalEmail.String
+	student.VerificationNote = verificationNote.String
+	student.VerifiedBy = verifiedBy.String
+	if verifiedAt.Valid {
+		student.VerifiedAt = &verifiedAt.Time
+	}
+	return &student, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,31 @@
+package student
+
+import (
+	"backend/pkg/auth"
+	"backend/services/studentd/entity"
+)
+
+type Repository interface {
+	Writer
+	Reader
+}
+
+type Writer interface {
+	CreateStudent(student *entity.Student) error
+	UpdateStudent(student *entity.Student) error
+	SetVerification(student *entity.Student) error
+}
+
+type Reader interface {
+	GetStudent(institutionID, userID string) (*entity.Student, error)
+	GetStudentByRollNumber(institutionID, rollNumber string) (*entity.Student, error)
+	ListStudents(filter entity.StudentFilter) ([]*entity.Student, error)
+}
+
+type Usecase interface {
+	GetOwnProfile(principal *auth.Principal) (*entity.Student, error)
+	SaveOwnProfile(principal *auth.Principal, details entity.StudentDetails) (*entity.Student, error)
+	GetStudent(principal *auth.Principal, userID string) (*entity.Student, error)
+	ListStudents(principal *auth.Principal, filter entity.StudentFilter, cursor string) ([]*entity.Student, string, error)
+	VerifyStudent(principal *auth.Principal, userID string, version int, approved bool, note string) (*entity.Student, error)
+}
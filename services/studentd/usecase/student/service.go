@@ -0,0 +1,178 @@
+package student
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/studentd/entity"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"log"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+var (
+	ErrStudentNotFound = errors.New("student not found")
+	ErrRollNumberTaken = errors.New("roll number is already used by another student")
+	ErrInvalidCursor   = errors.New("invalid cursor")
+)
+
+type Service struct {
+	repo  Repository
+	authz *auth.Authorizer
+}
+
+func NewService(repo Repository, authz *auth.Authorizer) *Service {
+	return &Service{
+		repo:  repo,
+		authz: authz,
+	}
+}
+
+// GetOwnProfile returns the caller's student profile.
+func (s *Service) GetOwnProfile(principal *auth.Principal) (*entity.Student, error) {
+	if err := s.authz.Authorize(principal, common.StudentProfile); err != nil {
+		return nil, err
+	}
+	return s.getStudent(principal.InstitutionID, principal.UserID)
+}
+
+// SaveOwnProfile creates the caller's student profile or updates it.
+func (s *Service) SaveOwnProfile(principal *auth.Principal, details entity.StudentDetails) (*entity.Student, error) {
+	if err := s.authz.Authorize(principal, common.StudentProfile); err != nil {
+		return nil, err
+	}
+
+	student, err := s.repo.GetStudent(principal.InstitutionID, principal.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		student, err = entity.NewStudent(principal.UserID, principal.InstitutionID, details)
+		if err != nil {
+			log.Printf("unable to create student entity, err=%v", err)
+			return nil, err
+		}
+		if err := s.checkRollNumberFree(student); err != nil {
+			return nil, err
+		}
+		if err := s.repo.CreateStudent(student); err != nil {
+			log.Printf("unable to create student in repository, err=%v", err)
+			return nil, err
+		}
+		return student, nil
+	} else if err != nil {
+		log.Printf("unable to get student %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+
+	if err := student.Update(details); err != nil {
+		log.Printf("unable to update student entity, err=%v", err)
+		return nil, err
+	}
+	if err := s.checkRollNumberFree(student); err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateStudent(student); errors.Is(err, entity.ErrStudentChanged) {
+		return nil, err
+	} else if err != nil {
+		log.Printf("unable to update student %s in repository, err=%v", student.UserID, err)
+		return nil, err
+	}
+	return student, nil
+}
+
+func (s *Service) GetStudent(principal *auth.Principal, userID string) (*entity.Student, error) {
+	if err := s.authz.Authorize(principal, common.StudentRead); err != nil {
+		return nil, err
+	}
+	return s.getStudent(principal.InstitutionID, userID)
+}
+
+// ListStudents returns one page of the students of the caller's institution
+// and the cursor of the next page, which is empty on the last page.
+func (s *Service) ListStudents(principal *auth.Principal, filter entity.StudentFilter, cursor string) ([]*entity.Student, string, error) {
+	if err := s.authz.Authorize(principal, common.StudentRead); err != nil {
+		return nil, "", err
+	}
+	filter.InstitutionID = principal.InstitutionID
+
+	if cursor != "" {
+		after, err := base64.RawURLEncoding.DecodeString(cursor)
+		if err != nil || len(after) == 0 {
+			return nil, "", ErrInvalidCursor
+		}
+		filter.After = string(after)
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	} else if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to know whether there is a next page
+	filter.Limit++
+	students, err := s.repo.ListStudents(filter)
+	if err != nil {
+		log.Printf("unable to list students, err=%v", err)
+		return nil, "", err
+	}
+
+	if len(students) <= pageSize {
+		return students, "", nil
+	}
+	students = students[:pageSize]
+	return students, base64.RawURLEncoding.EncodeToString([]byte(students[len(students)-1].RollNumber)), nil
+}
+
+// VerifyStudent records whether an officer confirmed the academic record of
+// a student against the institution's records. version is the profile
+// version the officer reviewed; if the student saved since, the decision is
+// refused with entity.ErrStudentChanged.
+func (s *Service) VerifyStudent(principal *auth.Principal, userID string, version int, approved bool, note string) (*entity.Student, error) {
+	if err := s.authz.Authorize(principal, common.StudentVerify); err != nil {
+		return nil, err
+	}
+
+	student, err := s.getStudent(principal.InstitutionID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if student.Version != version {
+		return nil, entity.ErrStudentChanged
+	}
+	if err := student.Verify(principal.UserID, approved, note); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetVerification(student); errors.Is(err, entity.ErrStudentChanged) {
+		return nil, err
+	} else if err != nil {
+		log.Printf("unable to set verification of student %s, err=%v", userID, err)
+		return nil, err
+	}
+	return student, nil
+}
+
+func (s *Service) getStudent(institutionID, userID string) (*entity.Student, error) {
+	student, err := s.repo.GetStudent(institutionID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrStudentNotFound
+	} else if err != nil {
+		log.Printf("unable to get student %s, err=%v", userID, err)
+		return nil, err
+	}
+	return student, nil
+}
+
+func (s *Service) checkRollNumberFree(student *entity.Student) error {
+	existing, err := s.repo.GetStudentByRollNumber(student.InstitutionID, student.RollNumber)
+	if err == nil && existing.UserID != student.UserID {
+		return ErrRollNumberTaken
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("unable to get student by roll number, err=%v", err)
+		return err
+	}
+	return nil
+}
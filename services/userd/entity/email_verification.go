@@ -0,0 +1,37 @@
+package entity
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// EmailVerification proves that a user can receive mail at Email. It stops
+// counting once the user changes to another address.
+type EmailVerification struct {
+	TokenID   string
+	UserID    string
+	Email     string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// NewEmailVerification returns the entity to store and the raw token to email to the user.
+func NewEmailVerification(userID, email string, ttl time.Duration) (*EmailVerification, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &EmailVerification{
+		TokenID:   uuid.NewString(),
+		UserID:    userID,
+		Email:     email,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
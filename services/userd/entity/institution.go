@@ -0,0 +1,56 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Institution is a college the platform runs for. Users and companies
+// belong to exactly one and are only visible within it.
+type Institution struct {
+	InstitutionID string
+	Name          string
+	CreatedAt     time.Time
+	UpdatedAt     time.Time
+}
+
+func NewInstitution(name string) (*Institution, error) {
+	now := time.Now()
+	institution := &Institution{
+		InstitutionID: uuid.NewString(),
+		Name:          strings.TrimSpace(name),
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+
+	if err := institution.Validate(); err != nil {
+		return nil, err
+	}
+
+	return institution, nil
+}
+
+// Rename changes the name, keeping the old one if the new one is invalid.
+func (i *Institution) Rename(name string) error {
+	updated := *i
+	updated.Name = strings.TrimSpace(name)
+	if err := updated.Validate(); err != nil {
+		return err
+	}
+	i.Name = updated.Name
+	i.UpdatedAt = time.Now()
+	return nil
+}
+
+func (i *Institution) Validate() error {
+	if i.Name == "" {
+		return errors.New("institution name cannot be empty")
+	}
+	if len(i.Name) > 255 {
+		return errors.New("institution name cannot be longer than 255 characters")
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package entity
+
+import (
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invite lets the holder of a single-use token create an account with a
+// preset email and role.
+type Invite struct {
+	InviteID string
+	// InstitutionID is the institution the invitee joins
+	InstitutionID string
+	Email         string
+	Role          string
+	TokenHash     string
+	InvitedBy     string
+	ExpiresAt     time.Time
+	RedeemedAt    *time.Time
+	CreatedAt     time.Time
+}
+
+// NewInvite returns the entity to store and the raw token to hand to the invitee.
+func NewInvite(institutionID, email, role, invitedBy string, ttl time.Duration) (*Invite, string, error) {
+	if email == "" {
+		return nil, "", errors.New("email cannot be empty")
+	} else if !emailRegex.MatchString(email) {
+		return nil, "", errors.New("invalid email format")
+	}
+	if role == "" {
+		return nil, "", errors.New("role cannot be empty")
+	}
+
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &Invite{
+		InviteID:      uuid.NewString(),
+		InstitutionID: institutionID,
+		Email:         email,
+		Role:          role,
+		TokenHash:     HashToken(token),
+		InvitedBy:     invitedBy,
+		ExpiresAt:     now.Add(ttl),
+		CreatedAt:     now,
+	}, token, nil
+}
@@ -0,0 +1,85 @@
+package entity
+
+import (
+	"crypto/rand"
+	"encoding/base32"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TOTP is a user's authenticator app enrollment. It only counts as a second
+// factor once confirmed with a first code.
+type TOTP struct {
+	UserID string
+	// EnrollmentID is new for every enrollment
+	EnrollmentID string
+	Secret       string
+	ConfirmedAt  *time.Time
+	LastUsedStep int64
+	CreatedAt    time.Time
+}
+
+func (t *TOTP) IsConfirmed() bool {
+	return t.ConfirmedAt != nil
+}
+
+// MFAChallenge is handed out by a login with the right password and traded
+// for tokens together with a second factor.
+type MFAChallenge struct {
+	ChallengeID string
+	UserID      string
+	DeviceID    string
+	TokenHash   string
+	ExpiresAt   time.Time
+	UsedAt      *time.Time
+	CreatedAt   time.Time
+}
+
+// NewMFAChallenge returns the entity to store and the raw token to hand to the client.
+func NewMFAChallenge(userID, deviceID string, ttl time.Duration) (*MFAChallenge, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &MFAChallenge{
+		ChallengeID: uuid.NewString(),
+		UserID:      userID,
+		DeviceID:    deviceID,
+		TokenHash:   HashToken(token),
+		ExpiresAt:   now.Add(ttl),
+		CreatedAt:   now,
+	}, token, nil
+}
+
+// LoginResult is either the tokens of a finished login or, for users with
+// a second factor, the challenge to complete it.
+type LoginResult struct {
+	Tokens       *Tokens
+	MFAToken     string
+	MFAExpiresAt time.Time
+}
+
+var backupCodeEncoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// NewBackupCodes returns n single-use recovery codes formatted as XXXXX-XXXXX.
+func NewBackupCodes(n int) ([]string, error) {
+	codes := make([]string, n)
+	for i := range codes {
+		raw := make([]byte, 7)
+		if _, err := rand.Read(raw); err != nil {
+			return nil, err
+		}
+		code := backupCodeEncoding.EncodeToString(raw)[:10]
+		codes[i] = code[:5] + "-" + code[5:]
+	}
+	return codes, nil
+}
+
+// HashBackupCode hashes a backup code the way it is stored, ignoring case and dashes.
+func HashBackupCode(code string) string {
+	return HashToken(strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(code), "-", "")))
+}
@@ -0,0 +1,45 @@
+package entity
+
+import (
+	"time"
+)
+
+// OIDCLogin remembers a single sign-on login between the redirect to the
+// identity provider and its callback. It is looked up by the hash of the
+// state parameter and carries the nonce and PKCE verifier of that login.
+type OIDCLogin struct {
+	StateHash    string
+	Nonce        string
+	CodeVerifier string
+	DeviceID     string
+	ExpiresAt    time.Time
+	UsedAt       *time.Time
+	CreatedAt    time.Time
+}
+
+// NewOIDCLogin returns the entity to store and the raw state to send to the provider.
+func NewOIDCLogin(deviceID string, ttl time.Duration) (*OIDCLogin, string, error) {
+	state, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	nonce, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+	// 43 URL-safe characters, a valid RFC 7636 code verifier
+	verifier, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &OIDCLogin{
+		StateHash:    HashToken(state),
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		DeviceID:     deviceID,
+		ExpiresAt:    now.Add(ttl),
+		CreatedAt:    now,
+	}, state, nil
+}
@@ -0,0 +1,55 @@
+package entity
+
+import (
+	"backend/pkg/common"
+	"errors"
+	"fmt"
+	"regexp"
+	"slices"
+	"time"
+)
+
+var roleNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_-]{1,63}$`)
+
+// ErrInvalidRole is wrapped by every validation error of a role.
+var ErrInvalidRole = errors.New("invalid role")
+
+type Role struct {
+	Name string
+	// InstitutionID is empty for roles shared by every institution
+	InstitutionID string
+	Description   string
+	Permissions   []string
+	// System roles ship with init.sql and cannot be deleted
+	IsSystem  bool
+	CreatedAt time.Time
+}
+
+func NewRole(name, description string, permissions []string) (*Role, error) {
+	role := &Role{
+		Name:        name,
+		Description: description,
+		Permissions: permissions,
+		CreatedAt:   time.Now(),
+	}
+
+	if err := role.Validate(); err != nil {
+		return nil, err
+	}
+
+	return role, nil
+}
+
+func (r *Role) Validate() error {
+	if !roleNameRegex.MatchString(r.Name) {
+		return fmt.Errorf("%w: name must be 2-64 lowercase letters, digits, '-' or '_'", ErrInvalidRole)
+	}
+	for _, permission := range r.Permissions {
+		if !slices.Contains(common.Actions, permission) {
+			return fmt.Errorf("%w: unknown permission %q", ErrInvalidRole, permission)
+		}
+	}
+	slices.Sort(r.Permissions)
+	r.Permissions = slices.Compact(r.Permissions)
+	return nil
+}
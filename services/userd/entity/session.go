@@ -0,0 +1,116 @@
+package entity
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session groups the refresh tokens issued to one device since a login.
+// Revoking it invalidates every access and refresh token carrying its ID.
+// MFA records whether the login passed a second factor.
+type Session struct {
+	SessionID string
+	UserID    string
+	DeviceID  string
+	// InstitutionID is the tenant the session acts in, at first the one
+	// of the user
+	InstitutionID string
+	MFA           bool
+	IP            string
+	UserAgent     string
+	CreatedAt     time.Time
+	LastSeenAt    time.Time
+	RevokedAt     *time.Time
+}
+
+// Client describes where a login came from.
+type Client struct {
+	IP        string
+	UserAgent string
+}
+
+type RefreshToken struct {
+	TokenID   string
+	SessionID string
+	TokenHash string
+	ExpiresAt time.Time
+	UsedAt    *time.Time
+	CreatedAt time.Time
+}
+
+// Tokens is the result of a login or a refresh.
+type Tokens struct {
+	AccessToken  string
+	RefreshToken string
+	DeviceID     string
+	ExpiresAt    time.Time
+}
+
+// maxUserAgentLen keeps arbitrary client headers within the sessions column.
+const maxUserAgentLen = 255
+
+func NewSession(userID, institutionID, deviceID string, client Client) *Session {
+	if deviceID == "" {
+		deviceID = uuid.NewString()
+	}
+	userAgent := client.UserAgent
+	if len(userAgent) > maxUserAgentLen {
+		userAgent = strings.ToValidUTF8(userAgent[:maxUserAgentLen], "")
+	}
+
+	now := time.Now()
+	return &Session{
+		SessionID:     uuid.NewString(),
+		UserID:        userID,
+		DeviceID:      deviceID,
+		InstitutionID: institutionID,
+		IP:            client.IP,
+		UserAgent:     userAgent,
+		CreatedAt:     now,
+		LastSeenAt:    now,
+	}
+}
+
+// NewRefreshToken returns the entity to store and the raw token to hand to the client.
+// Only the SHA-256 of the raw token is kept.
+func NewRefreshToken(sessionID string, ttl time.Duration) (*RefreshToken, string, error) {
+	token, err := generateToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	now := time.Now()
+	return &RefreshToken{
+		TokenID:   uuid.NewString(),
+		SessionID: sessionID,
+		TokenHash: HashToken(token),
+		ExpiresAt: now.Add(ttl),
+		CreatedAt: now,
+	}, token, nil
+}
+
+// generateToken returns a random URL-safe token for links and API clients.
+func generateToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(raw), nil
+}
+
+func HashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// NewRandomPassword returns a password for accounts that sign in through an
+// external directory and never use a local one.
+func NewRandomPassword() (string, error) {
+	return generateToken()
+}
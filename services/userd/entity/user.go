@@ -1,32 +1,44 @@
 package entity
 
 import (
-	"backend/pkg/common"
 	"errors"
 	"regexp"
-	"slices"
 	"time"
 )
 
 // helper
 var emailRegex = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
 
+// User statuses
+const (
+	UserActive      = "active"
+	UserDeactivated = "deactivated"
+)
+
 type User struct {
-	UserID    string
-	UserName  string
-	Email     string
-	Pass      string
-	Role      string
-	CreatedAt time.Time
+	UserID          string
+	InstitutionID   string
+	UserName        string
+	Email           string
+	Pass            string
+	Role            string
+	Status          string
+	EmailVerifiedAt *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
 }
 
-func NewUser(userName, email, pass, role string) (*User, error) {
+func NewUser(institutionID, userName, email, pass, role string) (*User, error) {
+	now := time.Now()
 	user := &User{
-		UserName:  userName,
-		Email:     email,
-		Pass:      pass,
-		Role:      role,
-		CreatedAt: time.Now(),
+		InstitutionID: institutionID,
+		UserName:      userName,
+		Email:         email,
+		Pass:          pass,
+		Role:          role,
+		Status:        UserActive,
+		CreatedAt:     now,
+		UpdatedAt:     now,
 	}
 
 	if err := user.validate(); err != nil {
@@ -36,6 +48,38 @@ func NewUser(userName, email, pass, role string) (*User, error) {
 	return user, nil
 }
 
+// UpdateProfile changes the user name and email, keeping the old values if the new ones are invalid.
+// A new email address has to be verified again.
+func (u *User) UpdateProfile(userName, email string) error {
+	updated := *u
+	updated.UserName = userName
+	updated.Email = email
+	if err := updated.validate(); err != nil {
+		return err
+	}
+
+	if email != u.Email {
+		u.EmailVerifiedAt = nil
+	}
+	u.UserName = userName
+	u.Email = email
+	u.UpdatedAt = time.Now()
+	return nil
+}
+
+func (u *User) IsActive() bool {
+	return u.Status == UserActive
+}
+
+func (u *User) IsEmailVerified() bool {
+	return u.EmailVerifiedAt != nil
+}
+
+func (u *User) MarkEmailVerified() {
+	now := time.Now()
+	u.EmailVerifiedAt = &now
+}
+
 func (u *User) validate() error {
 	if u.UserName == "" {
 		return errors.New("user name cannot be empty")
@@ -48,8 +92,27 @@ func (u *User) validate() error {
 	if u.Pass == "" {
 		return errors.New("password cannot be empty")
 	}
-	if !slices.Contains(common.ValidRoles, u.Role) {
-		return errors.New("invalid role")
+	// Whether the role exists is checked against the roles table by the usecase
+	if u.Role == "" {
+		return errors.New("role cannot be empty")
 	}
 	return nil
 }
+
+// UserFilter selects a page of the user directory, newest first.
+// After holds the position of the last user of the previous page.
+type UserFilter struct {
+	InstitutionID string
+	Role          string
+	Status        string
+	CreatedAfter  *time.Time
+	CreatedBefore *time.Time
+	Search        string
+	After         *UserCursor
+	Limit         int
+}
+
+type UserCursor struct {
+	CreatedAt time.Time
+	UserID    string
+}
@@ -0,0 +1,98 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func toAPIKeyResponse(k *entity.APIKey) presenter.APIKeyResponse {
+	return presenter.APIKeyResponse{
+		KeyID:      k.KeyID,
+		Name:       k.Name,
+		Prefix:     k.Prefix,
+		Scopes:     k.Scopes,
+		Active:     k.IsActive(),
+		ExpiresAt:  unixOrNil(k.ExpiresAt),
+		LastUsedAt: unixOrNil(k.LastUsedAt),
+		RevokedAt:  unixOrNil(k.RevokedAt),
+		CreatedAt:  k.CreatedAt.Unix(),
+	}
+}
+
+func unixOrNil(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	unix := t.Unix()
+	return &unix
+}
+
+func createAPIKey(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.APIKeyRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+		var expiresAt *time.Time
+		if req.ExpiresInDays < 0 {
+			http.Error(w, "expires_in_days cannot be negative", http.StatusBadRequest)
+			return
+		} else if req.ExpiresInDays > 0 {
+			t := time.Now().AddDate(0, 0, req.ExpiresInDays)
+			expiresAt = &t
+		}
+
+		key, token, err := service.CreateAPIKey(auth.FromContext(r.Context()), req.Name, req.Scopes, expiresAt)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to create API key, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(presenter.CreateAPIKeyResponse{
+			APIKeyResponse: toAPIKeyResponse(key),
+			Key:            token,
+		}); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func listAPIKeys(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		keys, err := service.ListAPIKeys(auth.FromContext(r.Context()))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list API keys, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.APIKeyResponse, 0, len(keys))
+		for _, key := range keys {
+			resp = append(resp, toAPIKeyResponse(key))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func revokeAPIKey(service user.Usecase, keyID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := service.RevokeAPIKey(auth.FromContext(r.Context()), keyID); err != nil {
+			http.Error(w, fmt.Sprintf("unable to revoke API key, err=%v", err), errorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
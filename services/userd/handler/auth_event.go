@@ -0,0 +1,90 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+)
+
+// listAuthEvents serves the security audit log:
+// GET /v1/audit/auth-events?type=&actor_id=&subject_id=&email=&ip=&from=&to=&cursor=&limit=
+func listAuthEvents(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter, err := parseEventFilter(query)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to parse query, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		events, next, err := service.ListAuthEvents(auth.FromContext(r.Context()), filter, query.Get("cursor"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list auth events, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := presenter.ListAuthEventsResponse{
+			Events:     make([]presenter.AuthEventResponse, 0, len(events)),
+			NextCursor: next,
+		}
+		for _, event := range events {
+			resp.Events = append(resp.Events, presenter.AuthEventResponse{
+				EventID:   event.EventID,
+				Type:      event.Type,
+				ActorID:   event.ActorID,
+				SubjectID: event.SubjectID,
+				Email:     event.Email,
+				IP:        event.IP,
+				Detail:    event.Detail,
+				CreatedAt: event.CreatedAt.Unix(),
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func parseEventFilter(query url.Values) (auth.EventFilter, error) {
+	filter := auth.EventFilter{
+		Type:      query.Get("type"),
+		ActorID:   query.Get("actor_id"),
+		SubjectID: query.Get("subject_id"),
+		Email:     query.Get("email"),
+		IP:        query.Get("ip"),
+	}
+
+	if filter.Type != "" && !slices.Contains(auth.EventTypes, filter.Type) {
+		return filter, fmt.Errorf("unknown event type %q", filter.Type)
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", v)
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.From, err = parseDate(query.Get("from")); err != nil {
+		return filter, err
+	}
+	if filter.To, err = parseDate(query.Get("to")); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
@@ -0,0 +1,148 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/institution"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// institutionErrorStatus maps institution usecase errors to HTTP status codes
+func institutionErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, institution.ErrInstitutionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, institution.ErrInstitutionExists):
+		return http.StatusConflict
+	default:
+		return errorStatus(err)
+	}
+}
+
+func toInstitutionResponse(i *entity.Institution) presenter.InstitutionResponse {
+	return presenter.InstitutionResponse{
+		InstitutionID: i.InstitutionID,
+		Name:          i.Name,
+		CreatedAt:     i.CreatedAt.Unix(),
+		UpdatedAt:     i.UpdatedAt.Unix(),
+	}
+}
+
+func listInstitutions(service institution.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		institutions, err := service.ListInstitutions(auth.FromContext(r.Context()))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list institutions, err=%v", err), institutionErrorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.InstitutionResponse, 0, len(institutions))
+		for _, found := range institutions {
+			resp = append(resp, toInstitutionResponse(found))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func createInstitution(service institution.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.InstitutionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		created, err := service.CreateInstitution(auth.FromContext(r.Context()), req.Name)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to create institution, err=%v", err), institutionErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toInstitutionResponse(created)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func getInstitution(service institution.Usecase, institutionID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		found, err := service.GetInstitution(auth.FromContext(r.Context()), institutionID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to get institution, err=%v", err), institutionErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toInstitutionResponse(found)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func renameInstitution(service institution.Usecase, institutionID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.InstitutionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := service.RenameInstitution(auth.FromContext(r.Context()), institutionID, req.Name)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to update institution, err=%v", err), institutionErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toInstitutionResponse(updated)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+// Register Institution Routes
+func RegisterInstitutionHandlers(service institution.Usecase, authn *auth.Authenticator) {
+	http.Handle("/v1/institutions", authn.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listInstitutions(service)(w, r) // GET
+		case http.MethodPost:
+			createInstitution(service)(w, r) // POST
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+	http.Handle("/v1/institutions/", authn.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Extract id from path /v1/institutions/{id}
+		institutionID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/institutions/"), "/")
+		if _, err := uuid.Parse(institutionID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid institution ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			getInstitution(service, institutionID)(w, r) // GET
+		case http.MethodPut:
+			renameInstitution(service, institutionID)(w, r) // PUT
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+}
@@ -0,0 +1,78 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+func createInvite(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.InviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		invite, token, err := service.CreateInvite(auth.FromContext(r.Context()), req.Email, req.Role, time.Duration(req.ExpiresInHours)*time.Hour)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to create invite, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(presenter.InviteResponse{
+			InviteID:  invite.InviteID,
+			Email:     invite.Email,
+			Role:      invite.Role,
+			Token:     token,
+			ExpiresAt: invite.ExpiresAt.Unix(),
+		}); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func redeemInvite(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.RedeemInviteRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		newUser, err := service.RedeemInvite(req.Token, req.UserName, req.Pass)
+		if errors.Is(err, user.ErrInvalidInvite) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		} else if err != nil {
+			if writeValidationError(w, err) {
+				return
+			}
+			http.Error(w, fmt.Sprintf("unable to redeem invite, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toUserResponse(newUser)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
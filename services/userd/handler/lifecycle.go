@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func updateProfile(service user.Usecase, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.UpdateProfileRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := service.UpdateProfile(auth.FromContext(r.Context()), id, req.UserName, req.Email)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to update user, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toUserResponse(updated)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func changePassword(service user.Usecase, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.ChangePasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := service.ChangePassword(auth.FromContext(r.Context()), id, req.OldPass, req.NewPass); err != nil {
+			if writeValidationError(w, err) {
+				return
+			}
+			http.Error(w, fmt.Sprintf("unable to change password, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func setUserActive(service user.Usecase, id string, active bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		principal := auth.FromContext(r.Context())
+		var err error
+		var updated *entity.User
+		if active {
+			updated, err = service.ReactivateUser(principal, id)
+		} else {
+			updated, err = service.DeactivateUser(principal, id)
+		}
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to change user status, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toUserResponse(updated)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func deleteUser(service user.Usecase, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		// Owned companies go to ?reassign_to={id}
+		reassignTo := r.URL.Query().Get("reassign_to")
+
+		if err := service.DeleteUser(auth.FromContext(r.Context()), id, reassignTo); err != nil {
+			http.Error(w, fmt.Sprintf("unable to delete user, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func unlockUser(service user.Usecase, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := service.UnlockUser(auth.FromContext(r.Context()), id); err != nil {
+			http.Error(w, fmt.Sprintf("unable to unlock user, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// listUsers serves the user directory:
+// GET /v1/users?role=&status=&created_after=&created_before=&q=&cursor=&limit=
+func listUsers(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		filter, err := parseUserFilter(query)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to parse query, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		users, next, err := service.ListUsers(auth.FromContext(r.Context()), filter, query.Get("cursor"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list users, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := presenter.ListUsersResponse{
+			Users:      make([]presenter.UserResponse, 0, len(users)),
+			NextCursor: next,
+		}
+		for _, u := range users {
+			resp.Users = append(resp.Users, toUserResponse(u))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func parseUserFilter(query url.Values) (entity.UserFilter, error) {
+	filter := entity.UserFilter{
+		Role:   query.Get("role"),
+		Status: query.Get("status"),
+		Search: query.Get("q"),
+	}
+
+	if filter.Status != "" && filter.Status != entity.UserActive && filter.Status != entity.UserDeactivated {
+		return filter, fmt.Errorf("unknown status %q", filter.Status)
+	}
+	if v := query.Get("limit"); v != "" {
+		limit, err := strconv.Atoi(v)
+		if err != nil || limit <= 0 {
+			return filter, fmt.Errorf("invalid limit %q", v)
+		}
+		filter.Limit = limit
+	}
+
+	var err error
+	if filter.CreatedAfter, err = parseDate(query.Get("created_after")); err != nil {
+		return filter, err
+	}
+	if filter.CreatedBefore, err = parseDate(query.Get("created_before")); err != nil {
+		return filter, err
+	}
+	return filter, nil
+}
+
+// parseDate accepts RFC 3339 timestamps or plain YYYY-MM-DD dates.
+func parseDate(v string) (*time.Time, error) {
+	if v == "" {
+		return nil, nil
+	}
+	for _, layout := range []string{time.RFC3339, time.DateOnly} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return &t, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid date %q", v)
+}
@@ -0,0 +1,130 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+func loginMFA(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.LoginMFARequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := service.LoginMFA(req.MFAToken, req.Code, clientOf(r))
+		if errors.Is(err, user.ErrInvalidMFAChallenge) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		} else if err != nil {
+			setRetryAfter(w, err)
+			http.Error(w, fmt.Sprintf("unable to login user, err=%v", err), errorStatus(err))
+			return
+		}
+
+		writeTokens(w, tokens)
+	}
+}
+
+func enrollTOTP(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		secret, uri, err := service.EnrollTOTP(auth.FromContext(r.Context()))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to enroll TOTP, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(presenter.TOTPEnrollmentResponse{
+			Secret:          secret,
+			ProvisioningURI: uri,
+		}); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func confirmTOTP(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.MFACodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		codes, err := service.ConfirmTOTP(auth.FromContext(r.Context()), req.Code)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to confirm TOTP, err=%v", err), errorStatus(err))
+			return
+		}
+		writeBackupCodes(w, codes)
+	}
+}
+
+func disableTOTP(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.MFACodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := service.DisableTOTP(auth.FromContext(r.Context()), req.Code); err != nil {
+			setRetryAfter(w, err)
+			http.Error(w, fmt.Sprintf("unable to disable TOTP, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func regenerateBackupCodes(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.MFACodeRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		codes, err := service.RegenerateBackupCodes(auth.FromContext(r.Context()), req.Code)
+		if err != nil {
+			setRetryAfter(w, err)
+			http.Error(w, fmt.Sprintf("unable to regenerate backup codes, err=%v", err), errorStatus(err))
+			return
+		}
+		writeBackupCodes(w, codes)
+	}
+}
+
+func writeBackupCodes(w http.ResponseWriter, codes []string) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(presenter.BackupCodesResponse{
+		BackupCodes: codes,
+	}); err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		return
+	}
+}
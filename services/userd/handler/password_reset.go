@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+func forgotPassword(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.ForgotPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		if err := service.ForgotPassword(req.Email); err != nil {
+			http.Error(w, fmt.Sprintf("unable to start password reset, err=%v", err), errorStatus(err))
+			return
+		}
+
+		// Accepted whether or not the email is registered
+		w.WriteHeader(http.StatusAccepted)
+	}
+}
+
+func resetPassword(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.ResetPasswordRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		err := service.ResetPassword(req.Token, req.NewPass)
+		if errors.Is(err, user.ErrInvalidResetToken) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		} else if err != nil {
+			if writeValidationError(w, err) {
+				return
+			}
+			http.Error(w, fmt.Sprintf("unable to reset password, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
@@ -0,0 +1,162 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/role"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// roleErrorStatus maps role usecase errors to HTTP status codes
+func roleErrorStatus(err error) int {
+	switch {
+	case errors.Is(err, role.ErrRoleNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, role.ErrRoleExists), errors.Is(err, role.ErrRoleInUse):
+		return http.StatusConflict
+	case errors.Is(err, role.ErrSystemRole), errors.Is(err, role.ErrAdminLockout), errors.Is(err, role.ErrSuperAdminLockout),
+		errors.Is(err, entity.ErrInvalidRole):
+		return http.StatusBadRequest
+	default:
+		return errorStatus(err)
+	}
+}
+
+func toRoleResponse(r *entity.Role) presenter.RoleResponse {
+	return presenter.RoleResponse{
+		Name:        r.Name,
+		Description: r.Description,
+		Permissions: r.Permissions,
+		IsSystem:    r.IsSystem,
+	}
+}
+
+func listRoles(service role.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		roles, err := service.ListRoles(auth.FromContext(r.Context()))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list roles, err=%v", err), roleErrorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.RoleResponse, 0, len(roles))
+		for _, found := range roles {
+			resp = append(resp, toRoleResponse(found))
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func createRole(service role.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.RoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		newRole, err := service.CreateRole(auth.FromContext(r.Context()), req.Name, req.Description, req.Permissions)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to create role, err=%v", err), roleErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toRoleResponse(newRole)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func getRole(service role.Usecase, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		found, err := service.GetRole(auth.FromContext(r.Context()), name)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to get role, err=%v", err), roleErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toRoleResponse(found)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func updateRole(service role.Usecase, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var req presenter.RoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		updated, err := service.UpdateRole(auth.FromContext(r.Context()), name, req.Description, req.Permissions)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to update role, err=%v", err), roleErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toRoleResponse(updated)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func deleteRole(service role.Usecase, name string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := service.DeleteRole(auth.FromContext(r.Context()), name); err != nil {
+			http.Error(w, fmt.Sprintf("unable to delete role, err=%v", err), roleErrorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+// Register Role Routes
+func RegisterRoleHandlers(service role.Usecase, authn *auth.Authenticator) {
+	http.Handle("/v1/roles", authn.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listRoles(service)(w, r) // GET
+		case http.MethodPost:
+			createRole(service)(w, r) // POST
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+	http.Handle("/v1/roles/", authn.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Extract name from path /v1/roles/{name}
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/roles/"), "/")
+		if name == "" {
+			http.Error(w, "role name is required", http.StatusBadRequest)
+			return
+		}
+
+		switch r.Method {
+		case http.MethodGet:
+			getRole(service, name)(w, r) // GET
+		case http.MethodPut:
+			updateRole(service, name)(w, r) // PUT
+		case http.MethodDelete:
+			deleteRole(service, name)(w, r) // DELETE
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})))
+}
@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/presenter"
+	"backend/services/userd/usecase/user"
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+func listSessions(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		principal := auth.FromContext(r.Context())
+		sessions, err := service.ListSessions(principal)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to list sessions, err=%v", err), errorStatus(err))
+			return
+		}
+
+		resp := make([]presenter.SessionResponse, 0, len(sessions))
+		for _, session := range sessions {
+			resp = append(resp, presenter.SessionResponse{
+				SessionID:  session.SessionID,
+				DeviceID:   session.DeviceID,
+				IP:         session.IP,
+				UserAgent:  session.UserAgent,
+				MFA:        session.MFA,
+				Current:    session.SessionID == principal.SessionID,
+				CreatedAt:  session.CreatedAt.Unix(),
+				LastSeenAt: session.LastSeenAt.Unix(),
+			})
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(resp); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func revokeSession(service user.Usecase, sessionID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if err := service.RevokeSession(auth.FromContext(r.Context()), sessionID); err != nil {
+			http.Error(w, fmt.Sprintf("unable to revoke session, err=%v", err), errorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func revokeUserSessions(service user.Usecase, userID string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		if err := service.RevokeUserSessions(auth.FromContext(r.Context()), userID); err != nil {
+			http.Error(w, fmt.Sprintf("unable to revoke sessions, err=%v", err), errorStatus(err))
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func switchInstitution(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.SwitchInstitutionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		tokens, err := service.SwitchInstitution(auth.FromContext(r.Context()), req.InstitutionID)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to switch institution, err=%v", err), errorStatus(err))
+			return
+		}
+
+		writeTokens(w, tokens)
+	}
+}
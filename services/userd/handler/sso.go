@@ -0,0 +1,53 @@
+package handler
+
+import (
+	"backend/services/userd/usecase/user"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// startOIDCLogin redirects the browser to the identity provider.
+func startOIDCLogin(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		authURL, err := service.StartOIDCLogin(r.URL.Query().Get("device_id"))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to start single sign-on, err=%v", err), errorStatus(err))
+			return
+		}
+
+		http.Redirect(w, r, authURL, http.StatusFound)
+	}
+}
+
+// finishOIDCLogin handles the redirect back from the identity provider.
+func finishOIDCLogin(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		query := r.URL.Query()
+		if idpErr := query.Get("error"); idpErr != "" {
+			http.Error(w, fmt.Sprintf("identity provider refused the login, err=%s %s", idpErr, query.Get("error_description")), http.StatusUnauthorized)
+			return
+		}
+
+		result, err := service.FinishOIDCLogin(r.Context(), query.Get("state"), query.Get("code"), clientOf(r))
+		if errors.Is(err, user.ErrInvalidOIDCState) {
+			http.Error(w, err.Error(), http.StatusGone)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("unable to complete single sign-on, err=%v", err), errorStatus(err))
+			return
+		}
+
+		writeLoginResult(w, result)
+	}
+}
@@ -1,16 +1,111 @@
 package handler
 
 import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/pkg/throttle"
+	"backend/services/userd/entity"
 	"backend/services/userd/presenter"
 	"backend/services/userd/usecase/user"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"log"
+	"math"
 	"net/http"
+	"strconv"
 	"strings"
 
 	"github.com/google/uuid"
 )
 
+// errorStatus maps usecase errors to HTTP status codes
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, auth.ErrUnauthenticated), errors.Is(err, user.ErrInvalidCredentials),
+		errors.Is(err, user.ErrInvalidMFACode):
+		return http.StatusUnauthorized
+	case errors.Is(err, auth.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, user.ErrUserDeactivated), errors.Is(err, user.ErrSSOEmailUnverified),
+		errors.Is(err, user.ErrSSONoRole):
+		return http.StatusForbidden
+	case isLocked(err):
+		return http.StatusLocked
+	case isThrottled(err):
+		return http.StatusTooManyRequests
+	case errors.Is(err, user.ErrUserNotFound), errors.Is(err, user.ErrSSODisabled), errors.Is(err, user.ErrAPIKeyNotFound),
+		errors.Is(err, user.ErrSessionNotFound), errors.Is(err, user.ErrInstitutionNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, user.ErrEmailTaken), errors.Is(err, user.ErrMFAAlreadyEnabled):
+		return http.StatusConflict
+	case errors.Is(err, user.ErrUnknownRole), errors.Is(err, user.ErrSelfAction), errors.Is(err, user.ErrReassignTarget),
+		errors.Is(err, user.ErrInvalidCursor), errors.Is(err, user.ErrMFANotEnabled), errors.Is(err, user.ErrInvalidAPIKey),
+		errors.As(err, new(*user.ValidationError)):
+		return http.StatusBadRequest
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeValidationError reports field-level problems as JSON. It returns
+// false, writing nothing, for any other error.
+func writeValidationError(w http.ResponseWriter, err error) bool {
+	var invalid *user.ValidationError
+	if !errors.As(err, &invalid) {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusBadRequest)
+	if err := json.NewEncoder(w).Encode(presenter.ValidationErrorResponse{
+		Error:  invalid.Error(),
+		Fields: invalid.Fields,
+	}); err != nil {
+		log.Printf("unable to encode validation error, err=%v", err)
+	}
+	return true
+}
+
+func isLocked(err error) bool {
+	var limited *throttle.LimitedError
+	return errors.As(err, &limited) && limited.Locked
+}
+
+func isThrottled(err error) bool {
+	var limited *throttle.LimitedError
+	return errors.As(err, &limited) && !limited.Locked
+}
+
+// setRetryAfter tells throttled clients when to try again.
+func setRetryAfter(w http.ResponseWriter, err error) {
+	var limited *throttle.LimitedError
+	if errors.As(err, &limited) {
+		w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(limited.RetryAfter.Seconds()))))
+	}
+}
+
+// clientOf describes the client of r for its session.
+func clientOf(r *http.Request) entity.Client {
+	return entity.Client{
+		IP:        common.ClientIP(r),
+		UserAgent: r.UserAgent(),
+	}
+}
+
+func toUserResponse(u *entity.User) presenter.UserResponse {
+	return presenter.UserResponse{
+		UserID:        u.UserID,
+		InstitutionID: u.InstitutionID,
+		UserName:      u.UserName,
+		Email:         u.Email,
+		Role:          u.Role,
+		Status:        u.Status,
+		EmailVerified: u.IsEmailVerified(),
+		CreatedAt:     u.CreatedAt.Unix(),
+	}
+}
+
 func getUserHealth(w http.ResponseWriter, r *http.Request) {
 	w.WriteHeader(http.StatusOK)
 	w.Write([]byte("success"))
@@ -29,22 +124,20 @@ func createUser(service user.Usecase) http.HandlerFunc {
 			return
 		}
 
-		newUser, err := service.CreateUser(req.UserName, req.Email, req.Pass, req.Role)
+		newUser, err := service.CreateUser(auth.FromContext(r.Context()), req.UserName, req.Email, req.Pass, req.Role)
 		if err == nil && newUser == nil {
 			http.Error(w, fmt.Sprintf("unable to create user entity, err=%v", err), http.StatusBadRequest)
 			return
 		} else if err != nil {
-			http.Error(w, fmt.Sprintf("unable to create user entity, err=%v", err), http.StatusInternalServerError)
+			if writeValidationError(w, err) {
+				return
+			}
+			http.Error(w, fmt.Sprintf("unable to create user entity, err=%v", err), errorStatus(err))
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(presenter.UserResponse{
-			UserID:   newUser.UserID,
-			UserName: newUser.UserName,
-			Email:    newUser.Email,
-			Role:     newUser.Role,
-		}); err != nil {
+		if err := json.NewEncoder(w).Encode(toUserResponse(newUser)); err != nil {
 			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
 			return
 		}
@@ -79,19 +172,14 @@ func getUserByID(service user.Usecase) http.HandlerFunc {
 			return
 		}
 
-		user, err := service.GetUserByID(id)
+		user, err := service.GetUserByID(auth.FromContext(r.Context()), id)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("unable to get user by id, err=%v", err), http.StatusInternalServerError)
+			http.Error(w, fmt.Sprintf("unable to get user by id, err=%v", err), errorStatus(err))
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(presenter.UserResponse{
-			UserID:   user.UserID,
-			UserName: user.UserName,
-			Email:    user.Email,
-			Role:     user.Role,
-		}); err != nil {
+		if err := json.NewEncoder(w).Encode(toUserResponse(user)); err != nil {
 			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
 			return
 		}
@@ -119,19 +207,41 @@ func getUserByEmail(service user.Usecase) http.HandlerFunc {
 			return
 		}
 
-		user, err := service.GetUserByEmail(email)
+		user, err := service.GetUserByEmail(auth.FromContext(r.Context()), email)
 		if err != nil {
-			http.Error(w, fmt.Sprintf("unable to get user by email, err=%v", err), http.StatusInternalServerError)
+			http.Error(w, fmt.Sprintf("unable to get user by email, err=%v", err), errorStatus(err))
 			return
 		}
 
 		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(presenter.UserResponse{
-			UserID:   user.UserID,
-			UserName: user.UserName,
-			Email:    user.Email,
-			Role:     user.Role,
-		}); err != nil {
+		if err := json.NewEncoder(w).Encode(toUserResponse(user)); err != nil {
+			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+	}
+}
+
+func assignRole(service user.Usecase, id string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPut {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.AssignRoleRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+			return
+		}
+
+		user, err := service.AssignRole(auth.FromContext(r.Context()), id, req.Role)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("unable to assign role, err=%v", err), errorStatus(err))
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		if err := json.NewEncoder(w).Encode(toUserResponse(user)); err != nil {
 			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
 			return
 		}
@@ -151,25 +261,211 @@ func login(service user.Usecase) http.HandlerFunc {
 			return
 		}
 
-		user, err := service.Login(req.Email, req.Pass)
+		result, err := service.Login(req.Email, req.Pass, req.DeviceID, clientOf(r))
 		if err != nil {
-			http.Error(w, fmt.Sprintf("unable to login user, err=%v", err), http.StatusInternalServerError)
+			setRetryAfter(w, err)
+			http.Error(w, fmt.Sprintf("unable to login user, err=%v", err), errorStatus(err))
 			return
 		}
 
-		w.WriteHeader(http.StatusOK)
-		if err := json.NewEncoder(w).Encode(presenter.LoginResponse{JWTToken: user}); err != nil {
-			http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		writeLoginResult(w, result)
+	}
+}
+
+// writeLoginResult writes the tokens of a login, or its MFA challenge.
+func writeLoginResult(w http.ResponseWriter, result *entity.LoginResult) {
+	if result.Tokens != nil {
+		writeTokens(w, result.Tokens)
+		return
+	}
+
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(presenter.MFAChallengeResponse{
+		MFARequired: true,
+		MFAToken:    result.MFAToken,
+		ExpiresAt:   result.MFAExpiresAt.Unix(),
+	}); err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		return
+	}
+}
+
+func refreshToken(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		var req presenter.RefreshRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
 			return
 		}
+
+		tokens, err := service.Refresh(req.RefreshToken, req.DeviceID)
+		if errors.Is(err, user.ErrInvalidRefreshToken) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("unable to refresh token, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		writeTokens(w, tokens)
+	}
+}
+
+func logout(service user.Usecase) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		// The refresh token is optional
+		var req presenter.LogoutRequest
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, fmt.Sprintf("unable to decode request body, err=%v", err), http.StatusBadRequest)
+				return
+			}
+		}
+
+		err := service.Logout(auth.FromContext(r.Context()), req.RefreshToken)
+		if errors.Is(err, auth.ErrUnauthenticated) {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, fmt.Sprintf("unable to logout user, err=%v", err), http.StatusInternalServerError)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+func writeTokens(w http.ResponseWriter, tokens *entity.Tokens) {
+	w.WriteHeader(http.StatusOK)
+	if err := json.NewEncoder(w).Encode(presenter.LoginResponse{
+		JWTToken:     tokens.AccessToken,
+		RefreshToken: tokens.RefreshToken,
+		DeviceID:     tokens.DeviceID,
+		ExpiresAt:    tokens.ExpiresAt.Unix(),
+	}); err != nil {
+		http.Error(w, fmt.Sprintf("unable to encode to JSON, err=%v", err), http.StatusInternalServerError)
+		return
 	}
 }
 
 // Register User Routes
-func RegisterUserHandlers(service user.Usecase) {
-	http.HandleFunc("/v1/user/health", getUserHealth)           // GET
-	http.HandleFunc("/v1/user", createUser(service))            // POST
-	http.HandleFunc("/v1/user/id/", getUserByID(service))       //v1/user/id/{id} // GET
-	http.HandleFunc("/v1/user/email/", getUserByEmail(service)) ////v1/user/id/{email} // GET
-	http.HandleFunc("/v1/login", login(service))
+func RegisterUserHandlers(service user.Usecase, authn *auth.Authenticator) {
+	handle := func(pattern string, handler http.HandlerFunc) {
+		http.Handle(pattern, authn.Middleware(handler))
+	}
+
+	handle("/v1/user/health", getUserHealth) // GET
+	handle("/v1/user", createUser(service))  // POST
+	handle("/v1/user/id/", func(w http.ResponseWriter, r *http.Request) {
+		// Split /v1/user/id/{id}/{action}
+		id, action, _ := strings.Cut(strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/user/id/"), "/"), "/")
+		if action == "" && r.Method == http.MethodGet {
+			getUserByID(service)(w, r) //v1/user/id/{id} // GET
+			return
+		}
+
+		if _, err := uuid.Parse(id); err != nil {
+			http.Error(w, fmt.Sprintf("invalid user ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+		switch {
+		case action == "" && r.Method == http.MethodPut:
+			updateProfile(service, id)(w, r) // PUT
+		case action == "" && r.Method == http.MethodDelete:
+			deleteUser(service, id)(w, r) // DELETE ?reassign_to={id}
+		case action == "role":
+			assignRole(service, id)(w, r) //v1/user/id/{id}/role // PUT
+		case action == "password":
+			changePassword(service, id)(w, r) //v1/user/id/{id}/password // PUT
+		case action == "deactivate":
+			setUserActive(service, id, false)(w, r) //v1/user/id/{id}/deactivate // POST
+		case action == "reactivate":
+			setUserActive(service, id, true)(w, r) //v1/user/id/{id}/reactivate // POST
+		case action == "unlock":
+			unlockUser(service, id)(w, r) //v1/user/id/{id}/unlock // POST
+		case action == "sessions":
+			revokeUserSessions(service, id)(w, r) //v1/user/id/{id}/sessions // DELETE
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	})
+	handle("/v1/user/email/", getUserByEmail(service))            ////v1/user/id/{email} // GET
+	handle("/v1/users", listUsers(service))                       // GET
+	handle("/v1/user/verify", verifyEmail(service))               // POST
+	handle("/v1/user/verify/resend", resendVerification(service)) // POST
+	handle("/v1/invites", createInvite(service))                  // POST
+	handle("/v1/invites/redeem", redeemInvite(service))           // POST
+	handle("/v1/password/forgot", forgotPassword(service))        // POST
+	handle("/v1/password/reset", resetPassword(service))          // POST
+	handle("/v1/login", login(service))                           // POST
+	handle("/v1/login/mfa", loginMFA(service))                    // POST
+	handle("/v1/login/oidc", startOIDCLogin(service))             // GET ?device_id=
+	handle("/v1/login/oidc/callback", finishOIDCLogin(service))   // GET ?state=&code=
+	handle("/v1/me/mfa/totp", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodPost:
+			enrollTOTP(service)(w, r)
+		case http.MethodDelete:
+			disableTOTP(service)(w, r) // {code}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	handle("/v1/me/mfa/totp/confirm", confirmTOTP(service))           // POST
+	handle("/v1/me/mfa/backup-codes", regenerateBackupCodes(service)) // POST
+	handle("/v1/me/api-keys", func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+			listAPIKeys(service)(w, r)
+		case http.MethodPost:
+			createAPIKey(service)(w, r) // {name, scopes, expires_in_days}
+		default:
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		}
+	})
+	handle("/v1/me/api-keys/", func(w http.ResponseWriter, r *http.Request) {
+		keyID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/me/api-keys/"), "/")
+		if _, err := uuid.Parse(keyID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid API key ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		revokeAPIKey(service, keyID)(w, r) //v1/me/api-keys/{id} // DELETE
+	})
+	handle("/v1/me/sessions", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		listSessions(service)(w, r)
+	})
+	handle("/v1/me/sessions/", func(w http.ResponseWriter, r *http.Request) {
+		sessionID := strings.Trim(strings.TrimPrefix(r.URL.Path, "/v1/me/sessions/"), "/")
+		if _, err := uuid.Parse(sessionID); err != nil {
+			http.Error(w, fmt.Sprintf("invalid session ID format: %v", err), http.StatusBadRequest)
+			return
+		}
+		if r.Method != http.MethodDelete {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		revokeSession(service, sessionID)(w, r) //v1/me/sessions/{id} // DELETE
+	})
+	handle("/v1/me/institution", switchInstitution(service)) // PUT {institution_id}
+	handle("/v1/audit/auth-events", listAuthEvents(service)) // GET
+	handle("/v1/logout", logout(service))                    // POST
+	handle("/v1/token/refresh", refreshToken(service))       // POST
 }
@@ -0,0 +1,69 @@
+#!/bin/bash
+
+# Password logins against the openldap container of docker-compose.yml,
+# seeded from ldap.ldif. AUTH_BACKENDS=ldap,password tries it first.
+BASE_URL="http://localhost:8080/v1"
+
+PASSED=0
+FAILED=0
+
+check() {
+    local test_name="$1"
+    local expected="$2"
+    local actual="$3"
+
+    echo "Running Test: $test_name"
+    echo "Expected: $expected"
+    echo "Actual: $actual"
+    if [ "$actual" == "$expected" ]; then
+        echo "✓ Test Passed"
+        ((PASSED++))
+    else
+        echo "✗ Test Failed"
+        ((FAILED++))
+    fi
+    echo "------------------------"
+}
+
+login() {
+    curl -s -w "\n%{http_code}" -X POST "$BASE_URL/login" \
+        -H "Content-Type: application/json" \
+        -d "{\"email\": \"$1\", \"pass\": \"$2\", \"device_id\": \"ldap-test\"}"
+}
+
+# A member of placement-staff is provisioned as a manager
+response=$(login "ldap.staff@university.edu" "staff@123")
+status_code=$(echo "$response" | tail -n1)
+check "Directory login" "200" "$status_code"
+STAFF_JWT=$(echo "$response" | sed '$d' | jq -r '.jwt_token // empty')
+
+role=$(curl -s -H "Authorization: Bearer $STAFF_JWT" \
+    "$BASE_URL/user/email/ldap.staff@university.edu" | jq -r '.role // empty')
+check "Group mapped to role" "manager" "$role"
+
+# Users in no mapped group get LDAP_DEFAULT_ROLE
+response=$(login "ldap.faculty@university.edu" "faculty@123")
+FACULTY_JWT=$(echo "$response" | sed '$d' | jq -r '.jwt_token // empty')
+role=$(curl -s -H "Authorization: Bearer $FACULTY_JWT" \
+    "$BASE_URL/user/email/ldap.faculty@university.edu" | jq -r '.role // empty')
+check "Default role" "user" "$role"
+
+status_code=$(login "ldap.staff@university.edu" "wrong-password" | tail -n1)
+check "Wrong directory password" "401" "$status_code"
+
+# An empty password must not become an anonymous bind
+status_code=$(login "ldap.staff@university.edu" "" | tail -n1)
+check "Empty directory password" "401" "$status_code"
+
+# Users the directory does not know fall back to local passwords
+response=$(curl -s -w "\n%{http_code}" -X POST "$BASE_URL/user" \
+    -H "Authorization: Bearer $STAFF_JWT" \
+    -H "Content-Type: application/json" \
+    -d '{"user_name": "Local Officer", "email": "local.officer@university.edu", "pass": "local@123", "role": "officer"}')
+check "Create local user" "200" "$(echo "$response" | tail -n1)"
+status_code=$(login "local.officer@university.edu" "local@123" | tail -n1)
+check "Local password fallback" "200" "$status_code"
+
+echo "Tests Passed: $PASSED"
+echo "Tests Failed: $FAILED"
+[ "$FAILED" -eq 0 ]
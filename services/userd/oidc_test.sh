@@ -0,0 +1,57 @@
+#!/bin/bash
+
+# Single sign-on against the mock-oidc container of docker-compose.yml.
+# Its issuer is http://mock-oidc:8090, which curl resolves to localhost here.
+BASE_URL="http://localhost:8080/v1"
+RESOLVE="mock-oidc:8090:127.0.0.1"
+
+PASSED=0
+FAILED=0
+
+check() {
+    local test_name="$1"
+    local expected="$2"
+    local actual="$3"
+
+    echo "Running Test: $test_name"
+    echo "Expected: $expected"
+    echo "Actual: $actual"
+    if [ "$actual" == "$expected" ]; then
+        echo "✓ Test Passed"
+        ((PASSED++))
+    else
+        echo "✗ Test Failed"
+        ((FAILED++))
+    fi
+    echo "------------------------"
+}
+
+# Start the login, we are redirected to the identity provider
+AUTH_URL=$(curl -s -o /dev/null -w "%{redirect_url}" "$BASE_URL/login/oidc?device_id=sso-test")
+check "Redirect to identity provider" "http://mock-oidc:8090/placement/authorize" "${AUTH_URL%%\?*}"
+
+# The mock logs us in without a form and redirects back with a code
+CALLBACK_URL=$(curl -s --resolve "$RESOLVE" -o /dev/null -w "%{redirect_url}" "$AUTH_URL")
+check "Redirect back to callback" "$BASE_URL/login/oidc/callback" "${CALLBACK_URL%%\?*}"
+
+response=$(curl -s -w "\n%{http_code}" "$CALLBACK_URL")
+status_code=$(echo "$response" | tail -n1)
+body=$(echo "$response" | sed '$d')
+check "Callback issues tokens" "200" "$status_code"
+SSO_JWT=$(echo "$body" | jq -r '.jwt_token // empty')
+
+# The user was provisioned with the role mapped from the placement-staff group
+role=$(curl -s -H "Authorization: Bearer $SSO_JWT" \
+    "$BASE_URL/user/email/sso.staff@university.edu" | jq -r '.role // empty')
+check "Group mapped to role" "manager" "$role"
+
+# A callback cannot be replayed
+status_code=$(curl -s -o /dev/null -w "%{http_code}" "$CALLBACK_URL")
+check "Callback replay" "410" "$status_code"
+
+status_code=$(curl -s -o /dev/null -w "%{http_code}" "$BASE_URL/login/oidc/callback?state=bogus&code=bogus")
+check "Unknown state" "410" "$status_code"
+
+echo "Tests Passed: $PASSED"
+echo "Tests Failed: $FAILED"
+[ "$FAILED" -eq 0 ]
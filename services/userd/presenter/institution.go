@@ -0,0 +1,16 @@
+package presenter
+
+type InstitutionRequest struct {
+	Name string `json:"name"`
+}
+
+type InstitutionResponse struct {
+	InstitutionID string `json:"institution_id"`
+	Name          string `json:"name"`
+	CreatedAt     int64  `json:"created_at"`
+	UpdatedAt     int64  `json:"updated_at"`
+}
+
+type SwitchInstitutionRequest struct {
+	InstitutionID string `json:"institution_id"`
+}
@@ -9,18 +9,172 @@ type UserRequest struct {
 }
 
 type UserResponse struct {
-	UserID   string `json:"user_id"`
+	UserID        string `json:"user_id"`
+	InstitutionID string `json:"institution_id"`
+	UserName      string `json:"user_name"`
+	Email         string `json:"email"`
+	Role          string `json:"role"`
+	Status        string `json:"status"`
+	EmailVerified bool   `json:"email_verified"`
+	CreatedAt     int64  `json:"created_at"`
+}
+
+type UpdateProfileRequest struct {
 	UserName string `json:"user_name"`
 	Email    string `json:"email"`
-	Role     string `json:"role"`
+}
+
+type ChangePasswordRequest struct {
+	OldPass string `json:"old_pass"`
+	NewPass string `json:"new_pass"`
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token"`
+}
+
+type ResendVerificationRequest struct {
+	Email string `json:"email"`
+}
+
+type ForgotPasswordRequest struct {
+	Email string `json:"email"`
+}
+
+type ResetPasswordRequest struct {
+	Token   string `json:"token"`
+	NewPass string `json:"new_pass"`
 }
 
 type LoginRequest struct {
 	UserName string `json:"user_name"`
 	Email    string `json:"email"`
 	Pass     string `json:"pass"`
+	DeviceID string `json:"device_id"`
 }
 
 type LoginResponse struct {
-	JWTToken string `json:"jwt_token"`
+	JWTToken     string `json:"jwt_token"`
+	RefreshToken string `json:"refresh_token"`
+	DeviceID     string `json:"device_id"`
+	ExpiresAt    int64  `json:"expires_at"`
+}
+
+// MFAChallengeResponse is returned by login instead of LoginResponse for
+// users with two-factor authentication.
+type MFAChallengeResponse struct {
+	MFARequired bool   `json:"mfa_required"`
+	MFAToken    string `json:"mfa_token"`
+	ExpiresAt   int64  `json:"expires_at"`
+}
+
+type LoginMFARequest struct {
+	MFAToken string `json:"mfa_token"`
+	Code     string `json:"code"`
+}
+
+type TOTPEnrollmentResponse struct {
+	Secret          string `json:"secret"`
+	ProvisioningURI string `json:"provisioning_uri"`
+}
+
+type MFACodeRequest struct {
+	Code string `json:"code"`
+}
+
+type BackupCodesResponse struct {
+	BackupCodes []string `json:"backup_codes"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+	DeviceID     string `json:"device_id"`
+}
+
+type LogoutRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type InviteRequest struct {
+	Email          string `json:"email"`
+	Role           string `json:"role"`
+	ExpiresInHours int    `json:"expires_in_hours"`
+}
+
+type InviteResponse struct {
+	InviteID  string `json:"invite_id"`
+	Email     string `json:"email"`
+	Role      string `json:"role"`
+	Token     string `json:"token"`
+	ExpiresAt int64  `json:"expires_at"`
+}
+
+type RedeemInviteRequest struct {
+	Token    string `json:"token"`
+	UserName string `json:"user_name"`
+	Pass     string `json:"pass"`
+}
+
+type ListUsersResponse struct {
+	Users      []UserResponse `json:"users"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+type APIKeyRequest struct {
+	Name   string   `json:"name"`
+	Scopes []string `json:"scopes"`
+	// Zero creates a key that never expires
+	ExpiresInDays int `json:"expires_in_days"`
+}
+
+type APIKeyResponse struct {
+	KeyID      string   `json:"key_id"`
+	Name       string   `json:"name"`
+	Prefix     string   `json:"prefix"`
+	Scopes     []string `json:"scopes"`
+	Active     bool     `json:"active"`
+	ExpiresAt  *int64   `json:"expires_at"`
+	LastUsedAt *int64   `json:"last_used_at"`
+	RevokedAt  *int64   `json:"revoked_at"`
+	CreatedAt  int64    `json:"created_at"`
+}
+
+// CreateAPIKeyResponse is the only time the key itself is returned.
+type CreateAPIKeyResponse struct {
+	APIKeyResponse
+	Key string `json:"key"`
+}
+
+type SessionResponse struct {
+	SessionID string `json:"session_id"`
+	DeviceID  string `json:"device_id"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user_agent"`
+	MFA       bool   `json:"mfa"`
+	// Current marks the session of the token that made the request
+	Current    bool  `json:"current"`
+	CreatedAt  int64 `json:"created_at"`
+	LastSeenAt int64 `json:"last_seen_at"`
+}
+
+type AuthEventResponse struct {
+	EventID   int64  `json:"event_id"`
+	Type      string `json:"type"`
+	ActorID   string `json:"actor_id,omitempty"`
+	SubjectID string `json:"subject_id,omitempty"`
+	Email     string `json:"email,omitempty"`
+	IP        string `json:"ip,omitempty"`
+	Detail    string `json:"detail,omitempty"`
+	CreatedAt int64  `json:"created_at"`
+}
+
+type ListAuthEventsResponse struct {
+	Events     []AuthEventResponse `json:"events"`
+	NextCursor string              `json:"next_cursor,omitempty"`
+}
+
+// ValidationErrorResponse lists the problems of each invalid request field.
+type ValidationErrorResponse struct {
+	Error  string              `json:"error"`
+	Fields map[string][]string `json:"fields"`
 }
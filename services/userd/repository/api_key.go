@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+func (r *Repository) CreateAPIKey(key *entity.APIKey) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO api_keys (key_id, user_id, name, prefix, key_hash, mfa_enrollment_id, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`, key.KeyID, key.UserID, key.Name, key.Prefix, key.KeyHash, nullString(key.MFAEnrollmentID), key.ExpiresAt, key.CreatedAt)
+	if err != nil {
+		return err
+	}
+	for _, scope := range key.Scopes {
+		if _, err := tx.Exec(`
+			INSERT INTO api_key_scopes (key_id, permission)
+			VALUES (?, ?);
+		`, key.KeyID, scope); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// ListAPIKeys returns the keys of a user, newest first, including revoked and expired ones.
+func (r *Repository) ListAPIKeys(userID string) ([]*entity.APIKey, error) {
+	rows, err := r.db.Query(`
+		SELECT
+			key_id, user_id, name, prefix, key_hash, expires_at, last_used_at, revoked_at, created_at
+			FROM api_keys WHERE user_id = ?
+			ORDER BY created_at DESC;
+	`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []*entity.APIKey
+	for rows.Next() {
+		var key entity.APIKey
+		var expiresAt, lastUsedAt, revokedAt sql.NullTime
+		if err := rows.Scan(&key.KeyID,
+			&key.UserID,
+			&key.Name,
+			&key.Prefix,
+			&key.KeyHash,
+			&expiresAt,
+			&lastUsedAt,
+			&revokedAt,
+			&key.CreatedAt); err != nil {
+			return nil, err
+		}
+		if expiresAt.Valid {
+			key.ExpiresAt = &expiresAt.Time
+		}
+		if lastUsedAt.Valid {
+			key.LastUsedAt = &lastUsedAt.Time
+		}
+		if revokedAt.Valid {
+			key.RevokedAt = &revokedAt.Time
+		}
+		keys = append(keys, &key)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, key := range keys {
+		if key.Scopes, err = r.getAPIKeyScopes(key.KeyID); err != nil {
+			return nil, err
+		}
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes a key of userID and reports whether there was such an unrevoked key.
+func (r *Repository) RevokeAPIKey(keyID, userID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE api_keys SET revoked_at = ?
+		WHERE key_id = ? AND user_id = ? AND revoked_at IS NULL;
+	`, time.Now(), keyID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// GetAPIKeyGrant implements auth.APIKeyStore.
+func (r *Repository) GetAPIKeyGrant(keyHash string) (*auth.APIKeyGrant, error) {
+	var grant auth.APIKeyGrant
+	var expiresAt, revokedAt, emailVerifiedAt sql.NullTime
+	var status string
+	err := r.db.QueryRow(`
+		SELECT
+			k.key_id, k.expires_at, k.revoked_at,
+			EXISTS (SELECT 1 FROM mfa_totp t
+				WHERE t.user_id = k.user_id AND t.enrollment_id = k.mfa_enrollment_id AND t.confirmed_at IS NOT NULL),
+			u.user_id, u.user_name, u.email, u.email_verified_at, u.role, u.status, u.institution_id
+			FROM api_keys k JOIN users u ON u.user_id = k.user_id
+			WHERE k.key_hash = ?;
+		`, keyHash).Scan(&grant.KeyID,
+		&expiresAt,
+		&revokedAt,
+		&grant.MFA,
+		&grant.UserID,
+		&grant.UserName,
+		&grant.Email,
+		&emailVerifiedAt,
+		&grant.Role,
+		&status,
+		&grant.InstitutionID)
+	if err != nil {
+		return nil, err
+	}
+	if expiresAt.Valid {
+		grant.ExpiresAt = &expiresAt.Time
+	}
+	if revokedAt.Valid {
+		grant.RevokedAt = &revokedAt.Time
+	}
+	grant.EmailVerified = emailVerifiedAt.Valid
+	grant.UserActive = status == entity.UserActive
+
+	if grant.Scopes, err = r.getAPIKeyScopes(grant.KeyID); err != nil {
+		return nil, err
+	}
+	return &grant, nil
+}
+
+// TouchAPIKey implements auth.APIKeyStore.
+func (r *Repository) TouchAPIKey(keyID string, usedAt, notBefore time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE api_keys SET last_used_at = ?
+		WHERE key_id = ? AND (last_used_at IS NULL OR last_used_at < ?);
+	`, usedAt, keyID, notBefore)
+	return err
+}
+
+func (r *Repository) getAPIKeyScopes(keyID string) ([]string, error) {
+	rows, err := r.db.Query("SELECT permission FROM api_key_scopes WHERE key_id = ? ORDER BY permission;", keyID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	scopes := []string{}
+	for rows.Next() {
+		var scope string
+		if err := rows.Scan(&scope); err != nil {
+			return nil, err
+		}
+		scopes = append(scopes, scope)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return scopes, nil
+}
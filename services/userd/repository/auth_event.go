@@ -0,0 +1,102 @@
+package repository
+
+import (
+	"backend/pkg/auth"
+	"database/sql"
+)
+
+// RecordEvent appends a security event. Empty fields are stored as NULL.
+func (r *Repository) RecordEvent(event *auth.Event) error {
+	result, err := r.db.Exec(`
+		INSERT INTO auth_events (type, institution_id, actor_id, subject_id, email, ip, detail, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?);
+	`, event.Type, nullString(event.InstitutionID), nullString(event.ActorID), nullString(event.SubjectID), nullString(event.Email),
+		nullString(event.IP), nullString(event.Detail), event.CreatedAt)
+	if err != nil {
+		return err
+	}
+	event.EventID, err = result.LastInsertId()
+	return err
+}
+
+// ListEvents returns the events matching filter, newest first.
+func (r *Repository) ListEvents(filter auth.EventFilter) ([]*auth.Event, error) {
+	query := `
+		SELECT
+			event_id, type, institution_id, actor_id, subject_id, email, ip, detail, created_at
+			FROM auth_events WHERE institution_id = ?`
+	args := []any{filter.InstitutionID}
+
+	if filter.Type != "" {
+		query += " AND type = ?"
+		args = append(args, filter.Type)
+	}
+	if filter.ActorID != "" {
+		query += " AND actor_id = ?"
+		args = append(args, filter.ActorID)
+	}
+	if filter.SubjectID != "" {
+		query += " AND subject_id = ?"
+		args = append(args, filter.SubjectID)
+	}
+	if filter.Email != "" {
+		query += " AND email = ?"
+		args = append(args, filter.Email)
+	}
+	if filter.IP != "" {
+		query += " AND ip = ?"
+		args = append(args, filter.IP)
+	}
+	if filter.From != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *filter.From)
+	}
+	if filter.To != nil {
+		query += " AND created_at < ?"
+		args = append(args, *filter.To)
+	}
+	if filter.Before > 0 {
+		query += " AND event_id < ?"
+		args = append(args, filter.Before)
+	}
+	query += " ORDER BY event_id DESC LIMIT ?;"
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var events []*auth.Event
+	for rows.Next() {
+		var event auth.Event
+		var institutionID, actorID, subjectID, email, ip, detail sql.NullString
+		if err := rows.Scan(&event.EventID,
+			&event.Type,
+			&institutionID,
+			&actorID,
+			&subjectID,
+			&email,
+			&ip,
+			&detail,
+			&event.CreatedAt); err != nil {
+			return nil, err
+		}
+		event.InstitutionID = institutionID.String
+		event.ActorID = actorID.String
+		event.SubjectID = subjectID.String
+		event.Email = email.String
+		event.IP = ip.String
+		event.Detail = detail.String
+		events = append(events, &event)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return events, nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+func (r *Repository) CreateEmailVerification(verification *entity.EmailVerification) error {
+	_, err := r.db.Exec(`
+		INSERT INTO email_verifications (token_id, user_id, email, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);
+	`, verification.TokenID, verification.UserID, verification.Email, verification.TokenHash, verification.ExpiresAt, verification.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetEmailVerificationByHash(tokenHash string) (*entity.EmailVerification, error) {
+	var verification entity.EmailVerification
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			token_id, user_id, email, token_hash, expires_at, used_at, created_at
+			FROM email_verifications WHERE token_hash = ?;
+		`, tokenHash).Scan(&verification.TokenID,
+		&verification.UserID,
+		&verification.Email,
+		&verification.TokenHash,
+		&verification.ExpiresAt,
+		&usedAt,
+		&verification.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		verification.UsedAt = &usedAt.Time
+	}
+	return &verification, nil
+}
+
+// ClaimEmailVerification marks a verification token as used and reports false
+// if it already was.
+func (r *Repository) ClaimEmailVerification(tokenID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE email_verifications SET used_at = ?
+		WHERE token_id = ? AND used_at IS NULL;
+	`, time.Now(), tokenID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
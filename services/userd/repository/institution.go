@@ -0,0 +1,73 @@
+package repository
+
+import "backend/services/userd/entity"
+
+func (r *Repository) CreateInstitution(institution *entity.Institution) error {
+	_, err := r.db.Exec(`
+		INSERT INTO institutions (institution_id, name, created_at, updated_at)
+		VALUES (?, ?, ?, ?);
+	`, institution.InstitutionID, institution.Name, institution.CreatedAt, institution.UpdatedAt)
+	return err
+}
+
+func (r *Repository) GetInstitution(institutionID string) (*entity.Institution, error) {
+	var institution entity.Institution
+	err := r.db.QueryRow(`
+		SELECT institution_id, name, created_at, updated_at
+		FROM institutions WHERE institution_id = ?;
+	`, institutionID).Scan(&institution.InstitutionID,
+		&institution.Name,
+		&institution.CreatedAt,
+		&institution.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &institution, nil
+}
+
+func (r *Repository) GetInstitutionByName(name string) (*entity.Institution, error) {
+	var institution entity.Institution
+	err := r.db.QueryRow(`
+		SELECT institution_id, name, created_at, updated_at
+		FROM institutions WHERE name = ?;
+	`, name).Scan(&institution.InstitutionID,
+		&institution.Name,
+		&institution.CreatedAt,
+		&institution.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &institution, nil
+}
+
+func (r *Repository) ListInstitutions() ([]*entity.Institution, error) {
+	rows, err := r.db.Query(`
+		SELECT institution_id, name, created_at, updated_at
+		FROM institutions ORDER BY name;
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var institutions []*entity.Institution
+	for rows.Next() {
+		var institution entity.Institution
+		if err := rows.Scan(&institution.InstitutionID,
+			&institution.Name,
+			&institution.CreatedAt,
+			&institution.UpdatedAt); err != nil {
+			return nil, err
+		}
+		institutions = append(institutions, &institution)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return institutions, nil
+}
+
+func (r *Repository) UpdateInstitution(institution *entity.Institution) error {
+	_, err := r.db.Exec("UPDATE institutions SET name = ? WHERE institution_id = ?;", institution.Name, institution.InstitutionID)
+	return err
+}
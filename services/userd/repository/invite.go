@@ -0,0 +1,63 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+func (r *Repository) CreateInvite(invite *entity.Invite) error {
+	_, err := r.db.Exec(`
+		INSERT INTO invites (invite_id, institution_id, email, role, role_scope, token_hash, invited_by, expires_at, created_at)
+		VALUES (?, ?, ?, ?, `+roleScope+`, ?, ?, ?, ?);
+	`, invite.InviteID, invite.InstitutionID, invite.Email, invite.Role, invite.InstitutionID, invite.Role, invite.TokenHash, invite.InvitedBy, invite.ExpiresAt, invite.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetInviteByHash(tokenHash string) (*entity.Invite, error) {
+	var invite entity.Invite
+	var redeemedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			invite_id, institution_id, email, role, token_hash, invited_by, expires_at, redeemed_at, created_at
+			FROM invites WHERE token_hash = ?;
+		`, tokenHash).Scan(&invite.InviteID,
+		&invite.InstitutionID,
+		&invite.Email,
+		&invite.Role,
+		&invite.TokenHash,
+		&invite.InvitedBy,
+		&invite.ExpiresAt,
+		&redeemedAt,
+		&invite.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if redeemedAt.Valid {
+		invite.RedeemedAt = &redeemedAt.Time
+	}
+	return &invite, nil
+}
+
+// ClaimInvite marks an invite as redeemed and reports false if it already was,
+// so an invite cannot be redeemed twice concurrently.
+func (r *Repository) ClaimInvite(inviteID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE invites SET redeemed_at = ?
+		WHERE invite_id = ? AND redeemed_at IS NULL;
+	`, time.Now(), inviteID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// ReleaseInvite undoes ClaimInvite when the account could not be created.
+func (r *Repository) ReleaseInvite(inviteID string) error {
+	_, err := r.db.Exec("UPDATE invites SET redeemed_at = NULL WHERE invite_id = ?;", inviteID)
+	return err
+}
@@ -0,0 +1,185 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+// SaveTOTP stores a new, unconfirmed enrollment, replacing any previous one.
+func (r *Repository) SaveTOTP(totp *entity.TOTP) error {
+	_, err := r.db.Exec(`
+		INSERT INTO mfa_totp (user_id, enrollment_id, secret, confirmed_at, last_used_step, created_at)
+		VALUES (?, ?, ?, NULL, 0, ?)
+		ON DUPLICATE KEY UPDATE enrollment_id = VALUES(enrollment_id), secret = VALUES(secret), confirmed_at = NULL, last_used_step = 0, created_at = VALUES(created_at);
+	`, totp.UserID, totp.EnrollmentID, totp.Secret, totp.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetTOTP(userID string) (*entity.TOTP, error) {
+	var totp entity.TOTP
+	var confirmedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			user_id, enrollment_id, secret, confirmed_at, last_used_step, created_at
+			FROM mfa_totp WHERE user_id = ?;
+		`, userID).Scan(&totp.UserID,
+		&totp.EnrollmentID,
+		&totp.Secret,
+		&confirmedAt,
+		&totp.LastUsedStep,
+		&totp.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if confirmedAt.Valid {
+		totp.ConfirmedAt = &confirmedAt.Time
+	}
+	return &totp, nil
+}
+
+// ConfirmTOTP activates an enrollment together with its first backup codes.
+func (r *Repository) ConfirmTOTP(userID string, step int64, backupCodeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`
+		UPDATE mfa_totp SET confirmed_at = ?, last_used_step = ?
+		WHERE user_id = ?;
+	`, time.Now(), step, userID); err != nil {
+		return err
+	}
+	if err := replaceBackupCodes(tx, userID, backupCodeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// ClaimTOTPStep records that the code of step was used and reports false if
+// that step or a later one was used already, so a code works only once.
+func (r *Repository) ClaimTOTPStep(userID string, step int64) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE mfa_totp SET last_used_step = ?
+		WHERE user_id = ? AND last_used_step < ?;
+	`, step, userID, step)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// DeleteTOTP removes the enrollment and the backup codes of a user.
+func (r *Repository) DeleteTOTP(userID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("DELETE FROM mfa_backup_codes WHERE user_id = ?;", userID); err != nil {
+		return err
+	}
+	if _, err := tx.Exec("DELETE FROM mfa_totp WHERE user_id = ?;", userID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) ReplaceBackupCodes(userID string, codeHashes []string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := replaceBackupCodes(tx, userID, codeHashes); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func replaceBackupCodes(tx *sql.Tx, userID string, codeHashes []string) error {
+	if _, err := tx.Exec("DELETE FROM mfa_backup_codes WHERE user_id = ?;", userID); err != nil {
+		return err
+	}
+	for _, hash := range codeHashes {
+		if _, err := tx.Exec(`
+			INSERT INTO mfa_backup_codes (user_id, code_hash)
+			VALUES (?, ?);
+		`, userID, hash); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// UseBackupCode marks a backup code as used and reports false if the user
+// has no such unused code.
+func (r *Repository) UseBackupCode(userID, codeHash string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE mfa_backup_codes SET used_at = ?
+		WHERE user_id = ? AND code_hash = ? AND used_at IS NULL;
+	`, time.Now(), userID, codeHash)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+func (r *Repository) CreateMFAChallenge(challenge *entity.MFAChallenge) error {
+	_, err := r.db.Exec(`
+		INSERT INTO mfa_challenges (challenge_id, user_id, device_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);
+	`, challenge.ChallengeID, challenge.UserID, challenge.DeviceID, challenge.TokenHash, challenge.ExpiresAt, challenge.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetMFAChallengeByHash(tokenHash string) (*entity.MFAChallenge, error) {
+	var challenge entity.MFAChallenge
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			challenge_id, user_id, device_id, token_hash, expires_at, used_at, created_at
+			FROM mfa_challenges WHERE token_hash = ?;
+		`, tokenHash).Scan(&challenge.ChallengeID,
+		&challenge.UserID,
+		&challenge.DeviceID,
+		&challenge.TokenHash,
+		&challenge.ExpiresAt,
+		&usedAt,
+		&challenge.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		challenge.UsedAt = &usedAt.Time
+	}
+	return &challenge, nil
+}
+
+// ClaimMFAChallenge marks a challenge as completed and reports false if it already was.
+func (r *Repository) ClaimMFAChallenge(challengeID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE mfa_challenges SET used_at = ?
+		WHERE challenge_id = ? AND used_at IS NULL;
+	`, time.Now(), challengeID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
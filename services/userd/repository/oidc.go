@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+func (r *Repository) CreateOIDCLogin(login *entity.OIDCLogin) error {
+	_, err := r.db.Exec(`
+		INSERT INTO oidc_logins (state_hash, nonce, code_verifier, device_id, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);
+	`, login.StateHash, login.Nonce, login.CodeVerifier, login.DeviceID, login.ExpiresAt, login.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetOIDCLogin(stateHash string) (*entity.OIDCLogin, error) {
+	var login entity.OIDCLogin
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			state_hash, nonce, code_verifier, device_id, expires_at, used_at, created_at
+			FROM oidc_logins WHERE state_hash = ?;
+		`, stateHash).Scan(&login.StateHash,
+		&login.Nonce,
+		&login.CodeVerifier,
+		&login.DeviceID,
+		&login.ExpiresAt,
+		&usedAt,
+		&login.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		login.UsedAt = &usedAt.Time
+	}
+	return &login, nil
+}
+
+// ClaimOIDCLogin marks a login as completed and reports false if it already was,
+// so a callback cannot be replayed. Logins that expired a day ago are purged.
+func (r *Repository) ClaimOIDCLogin(stateHash string) (bool, error) {
+	now := time.Now()
+	if _, err := r.db.Exec("DELETE FROM oidc_logins WHERE expires_at < ?;", now.Add(-24*time.Hour)); err != nil {
+		return false, err
+	}
+
+	result, err := r.db.Exec(`
+		UPDATE oidc_logins SET used_at = ?
+		WHERE state_hash = ? AND used_at IS NULL;
+	`, now, stateHash)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
@@ -0,0 +1,65 @@
+package repository
+
+import "time"
+
+// ReplacePassword sets a new password hash and moves the old one into the
+// user's history, keeping only the newest keep entries there.
+func (r *Repository) ReplacePassword(userID, oldHash, newHash string, keep int) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec("UPDATE users SET pass = ? WHERE user_id = ?;", newHash, userID); err != nil {
+		return err
+	}
+	if keep > 0 {
+		if _, err := tx.Exec(`
+			INSERT INTO password_history (user_id, pass_hash, created_at)
+			VALUES (?, ?, ?);
+		`, userID, oldHash, time.Now()); err != nil {
+			return err
+		}
+	}
+	// MySQL cannot LIMIT a subquery of the table being deleted from, hence the derived table
+	if _, err := tx.Exec(`
+		DELETE FROM password_history
+		WHERE user_id = ? AND history_id NOT IN (
+			SELECT history_id FROM (
+				SELECT history_id FROM password_history
+				WHERE user_id = ?
+				ORDER BY history_id DESC LIMIT ?
+			) AS newest
+		);
+	`, userID, userID, keep); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetPasswordHistory returns up to limit previous password hashes of a user, newest first.
+func (r *Repository) GetPasswordHistory(userID string, limit int) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT pass_hash FROM password_history
+		WHERE user_id = ?
+		ORDER BY history_id DESC LIMIT ?;
+	`, userID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []string
+	for rows.Next() {
+		var hash string
+		if err := rows.Scan(&hash); err != nil {
+			return nil, err
+		}
+		hashes = append(hashes, hash)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return hashes, nil
+}
@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+func (r *Repository) CreatePasswordReset(reset *entity.PasswordReset) error {
+	_, err := r.db.Exec(`
+		INSERT INTO password_resets (token_id, user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?);
+	`, reset.TokenID, reset.UserID, reset.TokenHash, reset.ExpiresAt, reset.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetPasswordResetByHash(tokenHash string) (*entity.PasswordReset, error) {
+	var reset entity.PasswordReset
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			token_id, user_id, token_hash, expires_at, used_at, created_at
+			FROM password_resets WHERE token_hash = ?;
+		`, tokenHash).Scan(&reset.TokenID,
+		&reset.UserID,
+		&reset.TokenHash,
+		&reset.ExpiresAt,
+		&usedAt,
+		&reset.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		reset.UsedAt = &usedAt.Time
+	}
+	return &reset, nil
+}
+
+// ClaimPasswordResets marks every unused reset token of a user as used and
+// reports false if tokenID was no longer among them, so a token cannot be
+// used twice and older emails stop working once one of them is.
+func (r *Repository) ClaimPasswordResets(userID, tokenID string) (bool, error) {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	now := time.Now()
+	result, err := tx.Exec(`
+		UPDATE password_resets SET used_at = ?
+		WHERE token_id = ? AND used_at IS NULL;
+	`, now, tokenID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	if rowsAffected != 1 {
+		return false, nil
+	}
+
+	if _, err := tx.Exec(`
+		UPDATE password_resets SET used_at = ?
+		WHERE user_id = ? AND used_at IS NULL;
+	`, now, userID); err != nil {
+		return false, err
+	}
+	return true, tx.Commit()
+}
@@ -0,0 +1,188 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+)
+
+// roleScope selects the scope of the role a user of an institution holds,
+// given the institution and the role name; see GetRole.
+const roleScope = "(SELECT scope FROM roles WHERE scope IN ('', ?) AND name = ?)"
+
+func (r *Repository) CreateRole(role *entity.Role) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.Exec(`
+		INSERT INTO roles (scope, name, institution_id, description, is_system, created_at)
+		VALUES (?, ?, ?, ?, ?, ?);
+	`, role.InstitutionID, role.Name, nullString(role.InstitutionID), role.Description, role.IsSystem, role.CreatedAt)
+	if err != nil {
+		return err
+	}
+	if err := insertRolePermissions(tx, role); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetRole returns a shared role or a custom role of the institution. Custom
+// roles never share a name with a shared one, so at most one role matches.
+func (r *Repository) GetRole(institutionID, name string) (*entity.Role, error) {
+	var role entity.Role
+	var roleInstitutionID sql.NullString
+	err := r.db.QueryRow(`
+		SELECT
+			name, institution_id, description, is_system, created_at
+			FROM roles WHERE scope IN ('', ?) AND name = ?;
+		`, institutionID, name).Scan(&role.Name,
+		&roleInstitutionID,
+		&role.Description,
+		&role.IsSystem,
+		&role.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	role.InstitutionID = roleInstitutionID.String
+
+	role.Permissions, err = r.GetRolePermissions(institutionID, name)
+	if err != nil {
+		return nil, err
+	}
+	return &role, nil
+}
+
+// ListRoles returns the shared roles and those of one institution.
+func (r *Repository) ListRoles(institutionID string) ([]*entity.Role, error) {
+	rows, err := r.db.Query(`
+		SELECT
+			name, institution_id, description, is_system, created_at
+			FROM roles WHERE institution_id IS NULL OR institution_id = ? ORDER BY name;
+	`, institutionID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var roles []*entity.Role
+	for rows.Next() {
+		var role entity.Role
+		var institutionID sql.NullString
+		if err := rows.Scan(&role.Name, &institutionID, &role.Description, &role.IsSystem, &role.CreatedAt); err != nil {
+			return nil, err
+		}
+		role.InstitutionID = institutionID.String
+		roles = append(roles, &role)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	for _, role := range roles {
+		role.Permissions, err = r.GetRolePermissions(institutionID, role.Name)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return roles, nil
+}
+
+// UpdateRole replaces the description and the full permission set of a role.
+func (r *Repository) UpdateRole(role *entity.Role) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.Exec("UPDATE roles SET description = ? WHERE scope = ? AND name = ?;", role.Description, role.InstitutionID, role.Name)
+	if err != nil {
+		return err
+	}
+	if rowsAffected, err := result.RowsAffected(); err != nil {
+		return err
+	} else if rowsAffected == 0 {
+		// MySQL reports 0 rows for an unchanged description, so check the role exists
+		var exists bool
+		if err := tx.QueryRow("SELECT EXISTS(SELECT 1 FROM roles WHERE scope = ? AND name = ?);", role.InstitutionID, role.Name).Scan(&exists); err != nil {
+			return err
+		}
+		if !exists {
+			return sql.ErrNoRows
+		}
+	}
+
+	if _, err := tx.Exec("DELETE FROM role_permissions WHERE scope = ? AND role = ?;", role.InstitutionID, role.Name); err != nil {
+		return err
+	}
+	if err := insertRolePermissions(tx, role); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// DeleteRole deletes a role of an institution, or a shared role when
+// institutionID is empty.
+func (r *Repository) DeleteRole(institutionID, name string) error {
+	result, err := r.db.Exec("DELETE FROM roles WHERE scope = ? AND name = ?;", institutionID, name)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// CountUsersWithRole counts the holders of a role of an institution, or of
+// a shared role when institutionID is empty.
+func (r *Repository) CountUsersWithRole(institutionID, name string) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM users WHERE role_scope = ? AND role = ?;", institutionID, name).Scan(&count)
+	return count, err
+}
+
+// GetRolePermissions returns the permissions of a role as seen by users of
+// an institution, like GetRole.
+func (r *Repository) GetRolePermissions(institutionID, role string) ([]string, error) {
+	rows, err := r.db.Query(`
+		SELECT permission FROM role_permissions
+			WHERE scope IN ('', ?) AND role = ? ORDER BY permission;
+	`, institutionID, role)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	permissions := []string{}
+	for rows.Next() {
+		var permission string
+		if err := rows.Scan(&permission); err != nil {
+			return nil, err
+		}
+		permissions = append(permissions, permission)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return permissions, nil
+}
+
+func insertRolePermissions(tx *sql.Tx, role *entity.Role) error {
+	for _, permission := range role.Permissions {
+		if _, err := tx.Exec(`
+			INSERT INTO role_permissions (scope, role, permission)
+			VALUES (?, ?, ?);
+		`, role.InstitutionID, role.Name, permission); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package repository
+
+import (
+	"backend/services/userd/entity"
+	"database/sql"
+	"time"
+)
+
+func (r *Repository) CreateSession(session *entity.Session) error {
+	_, err := r.db.Exec(`
+		INSERT INTO sessions (session_id, user_id, device_id, institution_id, mfa, ip, user_agent, created_at, last_seen_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+	`, session.SessionID, session.UserID, session.DeviceID, session.InstitutionID, session.MFA, session.IP, session.UserAgent, session.CreatedAt, session.LastSeenAt)
+	return err
+}
+
+func (r *Repository) GetSession(sessionID string) (*entity.Session, error) {
+	var session entity.Session
+	var revokedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			session_id, user_id, device_id, institution_id, mfa, ip, user_agent, created_at, last_seen_at, revoked_at
+			FROM sessions WHERE session_id = ?;
+		`, sessionID).Scan(&session.SessionID,
+		&session.UserID,
+		&session.DeviceID,
+		&session.InstitutionID,
+		&session.MFA,
+		&session.IP,
+		&session.UserAgent,
+		&session.CreatedAt,
+		&session.LastSeenAt,
+		&revokedAt)
+	if err != nil {
+		return nil, err
+	}
+	if revokedAt.Valid {
+		session.RevokedAt = &revokedAt.Time
+	}
+	return &session, nil
+}
+
+// SwitchSessionInstitution moves a live session to another institution.
+func (r *Repository) SwitchSessionInstitution(sessionID, institutionID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE sessions SET institution_id = ?
+		WHERE session_id = ? AND revoked_at IS NULL;
+	`, institutionID, sessionID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+func (r *Repository) RevokeSession(sessionID string) error {
+	_, err := r.db.Exec(`
+		UPDATE sessions SET revoked_at = ?
+		WHERE session_id = ? AND revoked_at IS NULL;
+	`, time.Now(), sessionID)
+	return err
+}
+
+func (r *Repository) CreateRefreshToken(token *entity.RefreshToken) error {
+	_, err := r.db.Exec(`
+		INSERT INTO refresh_tokens (token_id, session_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?);
+	`, token.TokenID, token.SessionID, token.TokenHash, token.ExpiresAt, token.CreatedAt)
+	return err
+}
+
+func (r *Repository) GetRefreshTokenByHash(tokenHash string) (*entity.RefreshToken, error) {
+	var token entity.RefreshToken
+	var usedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			token_id, session_id, token_hash, expires_at, used_at, created_at
+			FROM refresh_tokens WHERE token_hash = ?;
+		`, tokenHash).Scan(&token.TokenID,
+		&token.SessionID,
+		&token.TokenHash,
+		&token.ExpiresAt,
+		&usedAt,
+		&token.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if usedAt.Valid {
+		token.UsedAt = &usedAt.Time
+	}
+	return &token, nil
+}
+
+// MarkRefreshTokenUsed reports false if the token had already been used,
+// so two concurrent refreshes cannot both succeed.
+func (r *Repository) MarkRefreshTokenUsed(tokenID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE refresh_tokens SET used_at = ?
+		WHERE token_id = ? AND used_at IS NULL;
+	`, time.Now(), tokenID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// RevokeUserSessions signs a user out everywhere.
+func (r *Repository) RevokeUserSessions(userID string) error {
+	_, err := r.db.Exec(`
+		UPDATE sessions SET revoked_at = ?
+		WHERE user_id = ? AND revoked_at IS NULL;
+	`, time.Now(), userID)
+	return err
+}
+
+// RevokeUserSession revokes one session of userID and reports whether there
+// was such an unrevoked session.
+func (r *Repository) RevokeUserSession(sessionID, userID string) (bool, error) {
+	result, err := r.db.Exec(`
+		UPDATE sessions SET revoked_at = ?
+		WHERE session_id = ? AND user_id = ? AND revoked_at IS NULL;
+	`, time.Now(), sessionID, userID)
+	if err != nil {
+		return false, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return rowsAffected == 1, nil
+}
+
+// ListActiveSessions returns the unrevoked sessions of a user last seen after
+// seenAfter, most recently seen first.
+func (r *Repository) ListActiveSessions(userID string, seenAfter time.Time) ([]*entity.Session, error) {
+	rows, err := r.db.Query(`
+		SELECT
+			session_id, user_id, device_id, mfa, ip, user_agent, created_at, last_seen_at
+			FROM sessions
+			WHERE user_id = ? AND revoked_at IS NULL AND last_seen_at > ?
+			ORDER BY last_seen_at DESC;
+	`, userID, seenAfter)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var sessions []*entity.Session
+	for rows.Next() {
+		var session entity.Session
+		if err := rows.Scan(&session.SessionID,
+			&session.UserID,
+			&session.DeviceID,
+			&session.MFA,
+			&session.IP,
+			&session.UserAgent,
+			&session.CreatedAt,
+			&session.LastSeenAt); err != nil {
+			return nil, err
+		}
+		sessions = append(sessions, &session)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// TouchSession implements auth.SessionTracker.
+func (r *Repository) TouchSession(sessionID string, seenAt, notBefore time.Time) error {
+	_, err := r.db.Exec(`
+		UPDATE sessions SET last_seen_at = ?
+		WHERE session_id = ? AND last_seen_at < ?;
+	`, seenAt, sessionID, notBefore)
+	return err
+}
@@ -4,17 +4,27 @@ import (
 	"backend/services/userd/entity"
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Repository struct {
-	db *sql.DB
+	db        *sql.DB
+	companies CompanyReassigner
 }
 
-func NewUserRepository(db *sql.DB) *Repository {
+// CompanyReassigner moves company ownership inside a transaction of ours,
+// recording it in the company audit trail.
+type CompanyReassigner interface {
+	ReassignCompanies(tx *sql.Tx, institutionID, fromUserID, toUserID, actorID string) error
+}
+
+func NewUserRepository(db *sql.DB, companies CompanyReassigner) *Repository {
 	return &Repository{
-		db: db,
+		db:        db,
+		companies: companies,
 	}
 }
 
@@ -33,10 +43,10 @@ func (r *Repository) CreateUser(user *entity.User) (string, error) {
 	user.UserID = newUUID
 
 	query := `
-		INSERT INTO users (user_id, user_name, email, pass, role)
-		VALUES (?, ?, ?, ?, ?);
+		INSERT INTO users (user_id, institution_id, user_name, email, pass, role, role_scope, status, email_verified_at)
+		VALUES (?, ?, ?, ?, ?, ?, ` + roleScope + `, ?, ?);
 	`
-	_, err = r.db.Exec(query, user.UserID, user.UserName, user.Email, user.Pass, user.Role)
+	_, err = r.db.Exec(query, user.UserID, user.InstitutionID, user.UserName, user.Email, user.Pass, user.Role, user.InstitutionID, user.Role, user.Status, user.EmailVerifiedAt)
 	if err != nil {
 		return "", err
 	}
@@ -46,34 +56,220 @@ func (r *Repository) CreateUser(user *entity.User) (string, error) {
 
 func (r *Repository) GetUserByEmail(email string) (*entity.User, error) {
 	var user entity.User
+	var emailVerifiedAt sql.NullTime
 	err := r.db.QueryRow(`
 	SELECT 
-		user_id, user_name, email, pass, role 
+		user_id, institution_id, user_name, email, pass, role, status, email_verified_at, created_at, updated_at
 		FROM users WHERE email = ?;
 	`, email).Scan(&user.UserID,
+		&user.InstitutionID,
 		&user.UserName,
 		&user.Email,
 		&user.Pass,
-		&user.Role)
+		&user.Role,
+		&user.Status,
+		&emailVerifiedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
 	return &user, nil
 }
 
 func (r *Repository) GetUserByID(id string) (*entity.User, error) {
 	var user entity.User
+	var emailVerifiedAt sql.NullTime
 	err := r.db.QueryRow(`
 		SELECT 
-			user_id, user_name, email, pass, role 
+			user_id, institution_id, user_name, email, pass, role, status, email_verified_at, created_at, updated_at
 			FROM users WHERE user_id = ?;
 		`, id).Scan(&user.UserID,
+		&user.InstitutionID,
+		&user.UserName,
+		&user.Email,
+		&user.Pass,
+		&user.Role,
+		&user.Status,
+		&emailVerifiedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
+	return &user, nil
+}
+
+// GetInstitutionUser is GetUserByID for user IDs supplied by callers, which
+// must not reach users of other institutions.
+func (r *Repository) GetInstitutionUser(institutionID, id string) (*entity.User, error) {
+	var user entity.User
+	var emailVerifiedAt sql.NullTime
+	err := r.db.QueryRow(`
+		SELECT
+			user_id, institution_id, user_name, email, pass, role, status, email_verified_at, created_at, updated_at
+			FROM users WHERE user_id = ? AND institution_id = ?;
+		`, id, institutionID).Scan(&user.UserID,
+		&user.InstitutionID,
 		&user.UserName,
 		&user.Email,
 		&user.Pass,
-		&user.Role)
+		&user.Role,
+		&user.Status,
+		&emailVerifiedAt,
+		&user.CreatedAt,
+		&user.UpdatedAt)
 	if err != nil {
 		return nil, err
 	}
+	if emailVerifiedAt.Valid {
+		user.EmailVerifiedAt = &emailVerifiedAt.Time
+	}
 	return &user, nil
 }
+
+func (r *Repository) UpdatePassword(userID, passHash string) error {
+	_, err := r.db.Exec("UPDATE users SET pass = ? WHERE user_id = ?;", passHash, userID)
+	return err
+}
+
+func (r *Repository) UpdateUserRole(institutionID, userID, role string) error {
+	_, err := r.db.Exec("UPDATE users SET role = ?, role_scope = "+roleScope+" WHERE user_id = ? AND institution_id = ?;",
+		role, institutionID, role, userID, institutionID)
+	return err
+}
+
+func (r *Repository) UpdateUserProfile(user *entity.User) error {
+	_, err := r.db.Exec(`
+		UPDATE users SET user_name = ?, email = ?, email_verified_at = ?
+		WHERE user_id = ? AND institution_id = ?;
+	`, user.UserName, user.Email, user.EmailVerifiedAt, user.UserID, user.InstitutionID)
+	return err
+}
+
+func (r *Repository) MarkEmailVerified(userID string, verifiedAt time.Time) error {
+	_, err := r.db.Exec("UPDATE users SET email_verified_at = ? WHERE user_id = ?;", verifiedAt, userID)
+	return err
+}
+
+func (r *Repository) UpdateUserStatus(institutionID, userID, status string) error {
+	_, err := r.db.Exec("UPDATE users SET status = ? WHERE user_id = ? AND institution_id = ?;", status, userID, institutionID)
+	return err
+}
+
+func (r *Repository) CountOwnedCompanies(institutionID, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRow("SELECT COUNT(*) FROM company_data WHERE owner_id = ? AND institution_id = ?;", userID, institutionID).Scan(&count)
+	return count, err
+}
+
+// DeleteUser removes a user and revokes their sessions, handing their
+// companies to reassignTo on behalf of actorID. Sessions are kept as revoked
+// rather than deleted so tokens already issued for them are rejected.
+func (r *Repository) DeleteUser(institutionID, userID, reassignTo, actorID string) error {
+	tx, err := r.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if reassignTo != "" {
+		if err := r.companies.ReassignCompanies(tx, institutionID, userID, reassignTo, actorID); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.Exec("UPDATE sessions SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL;", time.Now(), userID); err != nil {
+		return err
+	}
+	result, err := tx.Exec("DELETE FROM users WHERE user_id = ? AND institution_id = ?;", userID, institutionID)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return tx.Commit()
+}
+
+func (r *Repository) ListUsers(filter entity.UserFilter) ([]*entity.User, error) {
+	query := `
+		SELECT
+			user_id, institution_id, user_name, email, pass, role, status, email_verified_at, created_at, updated_at
+			FROM users WHERE institution_id = ?`
+	args := []any{filter.InstitutionID}
+
+	if filter.Role != "" {
+		query += " AND role = ?"
+		args = append(args, filter.Role)
+	}
+	if filter.Status != "" {
+		query += " AND status = ?"
+		args = append(args, filter.Status)
+	}
+	if filter.CreatedAfter != nil {
+		query += " AND created_at >= ?"
+		args = append(args, *filter.CreatedAfter)
+	}
+	if filter.CreatedBefore != nil {
+		query += " AND created_at < ?"
+		args = append(args, *filter.CreatedBefore)
+	}
+	if filter.Search != "" {
+		prefix := escapeLike(filter.Search) + "%"
+		query += " AND (user_name LIKE ? OR email LIKE ?)"
+		args = append(args, prefix, prefix)
+	}
+	if filter.After != nil {
+		query += " AND (created_at < ? OR (created_at = ? AND user_id < ?))"
+		args = append(args, filter.After.CreatedAt, filter.After.CreatedAt, filter.After.UserID)
+	}
+	query += " ORDER BY created_at DESC, user_id DESC LIMIT ?;"
+	args = append(args, filter.Limit)
+
+	rows, err := r.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var users []*entity.User
+	for rows.Next() {
+		var user entity.User
+		var emailVerifiedAt sql.NullTime
+		if err := rows.Scan(&user.UserID,
+			&user.InstitutionID,
+			&user.UserName,
+			&user.Email,
+			&user.Pass,
+			&user.Role,
+			&user.Status,
+			&emailVerifiedAt,
+			&user.CreatedAt,
+			&user.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if emailVerifiedAt.Valid {
+			user.EmailVerifiedAt = &emailVerifiedAt.Time
+		}
+		users = append(users, &user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+// escapeLike makes user input match literally in a LIKE pattern.
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
@@ -0,0 +1,29 @@
+package institution
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+)
+
+type Repository interface {
+	Writer
+	Reader
+}
+
+type Writer interface {
+	CreateInstitution(institution *entity.Institution) error
+	UpdateInstitution(institution *entity.Institution) error
+}
+
+type Reader interface {
+	GetInstitution(institutionID string) (*entity.Institution, error)
+	GetInstitutionByName(name string) (*entity.Institution, error)
+	ListInstitutions() ([]*entity.Institution, error)
+}
+
+type Usecase interface {
+	CreateInstitution(principal *auth.Principal, name string) (*entity.Institution, error)
+	GetInstitution(principal *auth.Principal, institutionID string) (*entity.Institution, error)
+	ListInstitutions(principal *auth.Principal) ([]*entity.Institution, error)
+	RenameInstitution(principal *auth.Principal, institutionID, name string) (*entity.Institution, error)
+}
@@ -0,0 +1,118 @@
+package institution
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+)
+
+var (
+	ErrInstitutionNotFound = errors.New("institution not found")
+	ErrInstitutionExists   = errors.New("institution already exists")
+)
+
+// Service manages the institutions themselves, which only super-admins may
+// do. Everything else is scoped to the caller's current institution.
+type Service struct {
+	repo  Repository
+	authz *auth.Authorizer
+}
+
+func NewService(repo Repository, authz *auth.Authorizer) *Service {
+	return &Service{
+		repo:  repo,
+		authz: authz,
+	}
+}
+
+func (s *Service) CreateInstitution(principal *auth.Principal, name string) (*entity.Institution, error) {
+	if err := s.authz.Authorize(principal, common.InstitutionManage); err != nil {
+		return nil, err
+	}
+
+	institution, err := entity.NewInstitution(name)
+	if err != nil {
+		log.Printf("unable to create institution entity, err=%v", err)
+		return nil, err
+	}
+	if err := s.checkNameFree(institution.Name); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.CreateInstitution(institution); err != nil {
+		log.Printf("unable to create institution in repository, err=%v", err)
+		return nil, err
+	}
+	return institution, nil
+}
+
+func (s *Service) GetInstitution(principal *auth.Principal, institutionID string) (*entity.Institution, error) {
+	if err := s.authz.Authorize(principal, common.InstitutionManage); err != nil {
+		return nil, err
+	}
+	return s.getInstitution(institutionID)
+}
+
+func (s *Service) ListInstitutions(principal *auth.Principal) ([]*entity.Institution, error) {
+	if err := s.authz.Authorize(principal, common.InstitutionManage); err != nil {
+		return nil, err
+	}
+
+	institutions, err := s.repo.ListInstitutions()
+	if err != nil {
+		log.Printf("unable to list institutions, err=%v", err)
+		return nil, err
+	}
+	return institutions, nil
+}
+
+func (s *Service) RenameInstitution(principal *auth.Principal, institutionID, name string) (*entity.Institution, error) {
+	if err := s.authz.Authorize(principal, common.InstitutionManage); err != nil {
+		return nil, err
+	}
+
+	institution, err := s.getInstitution(institutionID)
+	if err != nil {
+		return nil, err
+	}
+	previous := institution.Name
+	if err := institution.Rename(name); err != nil {
+		log.Printf("unable to rename institution, err=%v", err)
+		return nil, err
+	}
+	if institution.Name != previous {
+		if err := s.checkNameFree(institution.Name); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := s.repo.UpdateInstitution(institution); err != nil {
+		log.Printf("unable to update institution in repository, err=%v", err)
+		return nil, err
+	}
+	return institution, nil
+}
+
+func (s *Service) getInstitution(institutionID string) (*entity.Institution, error) {
+	institution, err := s.repo.GetInstitution(institutionID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInstitutionNotFound
+	} else if err != nil {
+		log.Printf("unable to get institution, err=%v", err)
+		return nil, err
+	}
+	return institution, nil
+}
+
+func (s *Service) checkNameFree(name string) error {
+	if _, err := s.repo.GetInstitutionByName(name); err == nil {
+		return ErrInstitutionExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("unable to get institution by name, err=%v", err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,31 @@
+package role
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+)
+
+type Repository interface {
+	Writer
+	Reader
+}
+
+type Writer interface {
+	CreateRole(role *entity.Role) error
+	UpdateRole(role *entity.Role) error
+	DeleteRole(institutionID, name string) error
+}
+
+type Reader interface {
+	GetRole(institutionID, name string) (*entity.Role, error)
+	ListRoles(institutionID string) ([]*entity.Role, error)
+	CountUsersWithRole(institutionID, name string) (int, error)
+}
+
+type Usecase interface {
+	CreateRole(principal *auth.Principal, name, description string, permissions []string) (*entity.Role, error)
+	GetRole(principal *auth.Principal, name string) (*entity.Role, error)
+	ListRoles(principal *auth.Principal) ([]*entity.Role, error)
+	UpdateRole(principal *auth.Principal, name, description string, permissions []string) (*entity.Role, error)
+	DeleteRole(principal *auth.Principal, name string) error
+}
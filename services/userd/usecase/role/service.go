@@ -0,0 +1,189 @@
+package role
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+)
+
+var (
+	ErrRoleNotFound = errors.New("role not found")
+	ErrRoleExists   = errors.New("role already exists")
+	ErrRoleInUse    = errors.New("role is still assigned to users")
+	ErrSystemRole   = errors.New("built-in roles cannot be deleted")
+	ErrAdminLockout = errors.New("the admin role must keep the role:manage permission")
+	// ErrSuperAdminLockout keeps at least one role able to manage institutions
+	ErrSuperAdminLockout = errors.New("the superadmin role must keep the institution:manage permission")
+)
+
+type Service struct {
+	repo  Repository
+	authz *auth.Authorizer
+}
+
+func NewService(repo Repository, authz *auth.Authorizer) *Service {
+	return &Service{
+		repo:  repo,
+		authz: authz,
+	}
+}
+
+func (s *Service) CreateRole(principal *auth.Principal, name, description string, permissions []string) (*entity.Role, error) {
+	if err := s.authz.Authorize(principal, common.RoleManage); err != nil {
+		return nil, err
+	}
+
+	role, err := entity.NewRole(name, description, permissions)
+	if err != nil {
+		log.Printf("unable to create role entity, err=%v", err)
+		return nil, err
+	}
+	role.InstitutionID = principal.InstitutionID
+	if err := s.authorizeInstitutionWide(principal, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetRole(principal.InstitutionID, name); err == nil {
+		return nil, ErrRoleExists
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("unable to get role, err=%v", err)
+		return nil, err
+	}
+
+	if err := s.repo.CreateRole(role); err != nil {
+		log.Printf("unable to create role in repository, err=%v", err)
+		return nil, err
+	}
+	s.authz.Invalidate()
+	return role, nil
+}
+
+func (s *Service) GetRole(principal *auth.Principal, name string) (*entity.Role, error) {
+	if err := s.authz.Authorize(principal, common.RoleRead); err != nil {
+		return nil, err
+	}
+
+	return s.getRole(principal, name)
+}
+
+func (s *Service) ListRoles(principal *auth.Principal) ([]*entity.Role, error) {
+	if err := s.authz.Authorize(principal, common.RoleRead); err != nil {
+		return nil, err
+	}
+
+	roles, err := s.repo.ListRoles(principal.InstitutionID)
+	if err != nil {
+		log.Printf("unable to list roles, err=%v", err)
+		return nil, err
+	}
+	return roles, nil
+}
+
+func (s *Service) UpdateRole(principal *auth.Principal, name, description string, permissions []string) (*entity.Role, error) {
+	if err := s.authz.Authorize(principal, common.RoleManage); err != nil {
+		return nil, err
+	}
+
+	role, err := s.getRole(principal, name)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.authorizeSharedRole(principal, role); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeInstitutionWide(principal, role.Permissions); err != nil {
+		return nil, err
+	}
+
+	role.Description = description
+	role.Permissions = permissions
+	if err := role.Validate(); err != nil {
+		log.Printf("unable to validate role, err=%v", err)
+		return nil, err
+	}
+	if err := s.authorizeInstitutionWide(principal, role.Permissions); err != nil {
+		return nil, err
+	}
+	if role.Name == common.RoleAdmin && !slices.Contains(role.Permissions, common.RoleManage) {
+		return nil, ErrAdminLockout
+	}
+	if role.Name == common.RoleSuperAdmin && !slices.Contains(role.Permissions, common.InstitutionManage) {
+		return nil, ErrSuperAdminLockout
+	}
+
+	if err := s.repo.UpdateRole(role); err != nil {
+		log.Printf("unable to update role in repository, err=%v", err)
+		return nil, err
+	}
+	s.authz.Invalidate()
+	return role, nil
+}
+
+// getRole returns a role the caller's institution can see; the custom roles
+// of other institutions are reported as not found.
+func (s *Service) getRole(principal *auth.Principal, name string) (*entity.Role, error) {
+	role, err := s.repo.GetRole(principal.InstitutionID, name)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrRoleNotFound
+	} else if err != nil {
+		log.Printf("unable to get role, err=%v", err)
+		return nil, err
+	}
+	return role, nil
+}
+
+// authorizeSharedRole keeps changes to shared roles, the built-in ones
+// included, in the hands of super-admins: they apply to the users of every
+// institution.
+func (s *Service) authorizeSharedRole(principal *auth.Principal, role *entity.Role) error {
+	if role.InstitutionID != "" {
+		return nil
+	}
+	return s.authz.Authorize(principal, common.InstitutionManage)
+}
+
+// authorizeInstitutionWide keeps roles holding institution:manage, which
+// reaches every institution, in the hands of super-admins.
+func (s *Service) authorizeInstitutionWide(principal *auth.Principal, permissions []string) error {
+	if !slices.Contains(permissions, common.InstitutionManage) {
+		return nil
+	}
+	return s.authz.Authorize(principal, common.InstitutionManage)
+}
+
+func (s *Service) DeleteRole(principal *auth.Principal, name string) error {
+	if err := s.authz.Authorize(principal, common.RoleManage); err != nil {
+		return err
+	}
+
+	role, err := s.getRole(principal, name)
+	if err != nil {
+		return err
+	}
+	if role.IsSystem {
+		return ErrSystemRole
+	}
+	if err := s.authorizeSharedRole(principal, role); err != nil {
+		return err
+	}
+
+	users, err := s.repo.CountUsersWithRole(role.InstitutionID, name)
+	if err != nil {
+		log.Printf("unable to count users with role, err=%v", err)
+		return err
+	}
+	if users > 0 {
+		return ErrRoleInUse
+	}
+
+	if err := s.repo.DeleteRole(role.InstitutionID, name); err != nil {
+		log.Printf("unable to delete role in repository, err=%v", err)
+		return err
+	}
+	s.authz.Invalidate()
+	return nil
+}
@@ -0,0 +1,109 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+var (
+	ErrInvalidAPIKey  = errors.New("invalid API key")
+	ErrAPIKeyNotFound = errors.New("API key not found")
+	// ErrSessionRequired wraps auth.ErrForbidden for account management
+	// attempted with an API key instead of a logged in session
+	ErrSessionRequired = fmt.Errorf("%w: requires a logged in session, not an API key", auth.ErrForbidden)
+)
+
+// CreateAPIKey creates a key acting for the caller with the given scopes.
+// Callers can only hand a key permissions they hold themselves.
+func (s *Service) CreateAPIKey(principal *auth.Principal, name string, scopes []string, expiresAt *time.Time) (*entity.APIKey, string, error) {
+	if err := requireSession(principal); err != nil {
+		return nil, "", err
+	}
+
+	key, token, err := entity.NewAPIKey(principal.UserID, name, scopes, expiresAt)
+	if err != nil {
+		log.Printf("unable to create API key entity, err=%v", err)
+		return nil, "", fmt.Errorf("%w: %v", ErrInvalidAPIKey, err)
+	}
+	if principal.MFA {
+		if key.MFAEnrollmentID, err = s.mfaEnrollmentID(principal.UserID); err != nil {
+			return nil, "", err
+		}
+	}
+	for _, scope := range key.Scopes {
+		if err := s.authz.Authorize(principal, scope); err != nil {
+			return nil, "", err
+		}
+	}
+
+	if err := s.repo.CreateAPIKey(key); err != nil {
+		log.Printf("unable to create API key in repository, err=%v", err)
+		return nil, "", err
+	}
+	return key, token, nil
+}
+
+// mfaEnrollmentID returns the id of the confirmed second factor of a user,
+// empty if there is none.
+func (s *Service) mfaEnrollmentID(userID string) (string, error) {
+	enrollment, err := s.repo.GetTOTP(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", nil
+	} else if err != nil {
+		log.Printf("unable to get TOTP enrollment of user %s, err=%v", userID, err)
+		return "", err
+	}
+	if !enrollment.IsConfirmed() {
+		return "", nil
+	}
+	return enrollment.EnrollmentID, nil
+}
+
+// ListAPIKeys returns the caller's keys, including revoked and expired ones.
+func (s *Service) ListAPIKeys(principal *auth.Principal) ([]*entity.APIKey, error) {
+	if err := requireSession(principal); err != nil {
+		return nil, err
+	}
+
+	keys, err := s.repo.ListAPIKeys(principal.UserID)
+	if err != nil {
+		log.Printf("unable to list API keys of user %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	return keys, nil
+}
+
+// RevokeAPIKey revokes one of the caller's keys. It stops working on its next request.
+func (s *Service) RevokeAPIKey(principal *auth.Principal, keyID string) error {
+	if err := requireSession(principal); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeAPIKey(keyID, principal.UserID)
+	if err != nil {
+		log.Printf("unable to revoke API key %s, err=%v", keyID, err)
+		return err
+	}
+	if !revoked {
+		return ErrAPIKeyNotFound
+	}
+	s.recordTokenRevoked(principal, principal.UserID, "API key "+keyID)
+	return nil
+}
+
+// requireSession refuses API keys for managing the account itself, so a
+// leaked key cannot be used to mint further keys or take over the account.
+func requireSession(principal *auth.Principal) error {
+	if principal == nil {
+		return auth.ErrUnauthenticated
+	}
+	if principal.IsAPIKey() {
+		return ErrSessionRequired
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"log"
+	"strconv"
+)
+
+// ListAuthEvents returns one page of the security audit log, newest first,
+// and the cursor of the next page, which is empty on the last page.
+func (s *Service) ListAuthEvents(principal *auth.Principal, filter auth.EventFilter, cursor string) ([]*auth.Event, string, error) {
+	if err := s.authz.Authorize(principal, common.AuditRead); err != nil {
+		return nil, "", err
+	}
+	filter.InstitutionID = principal.InstitutionID
+
+	if cursor != "" {
+		before, err := strconv.ParseInt(cursor, 10, 64)
+		if err != nil || before <= 0 {
+			return nil, "", ErrInvalidCursor
+		}
+		filter.Before = before
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	} else if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to know whether there is a next page
+	filter.Limit++
+	events, err := s.repo.ListEvents(filter)
+	if err != nil {
+		log.Printf("unable to list auth events, err=%v", err)
+		return nil, "", err
+	}
+
+	if len(events) <= pageSize {
+		return events, "", nil
+	}
+	events = events[:pageSize]
+	return events, strconv.FormatInt(events[len(events)-1].EventID, 10), nil
+}
+
+// recordEvent appends to the security audit log, which is kept with the
+// users. Events about a user are listed within the user's institution.
+func (s *Service) recordEvent(event auth.Event) {
+	if event.InstitutionID == "" && event.SubjectID != "" {
+		if user, err := s.repo.GetUserByID(event.SubjectID); err == nil {
+			event.InstitutionID = user.InstitutionID
+		}
+	}
+	auth.RecordEvent(s.repo, event)
+}
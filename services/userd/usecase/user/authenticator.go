@@ -0,0 +1,151 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+)
+
+// Authenticator checks the email and password of a login against one
+// source of accounts. It returns ErrInvalidCredentials when the source
+// rejects them, so Login can fall back to the next one.
+type Authenticator interface {
+	Name() string
+	Authenticate(email, pass string) (*Authentication, error)
+}
+
+// Authentication is a successful check. Local accounts set User, external
+// directories set Identity and the role mapped from its groups.
+type Authentication struct {
+	User     *entity.User
+	Identity *auth.Identity
+	Role     string
+	Mapped   bool
+	// DefaultRole is given to new directory users none of whose groups are mapped
+	DefaultRole string
+}
+
+// Directory verifies passwords against an external directory such as LDAP,
+// returning auth.ErrInvalidCredentials when it rejects them.
+type Directory interface {
+	Authenticate(email, pass string) (*auth.Identity, error)
+}
+
+type passwordAuthenticator struct {
+	repo   Repository
+	hasher PasswordHasher
+}
+
+// NewPasswordAuthenticator checks passwords against the hashes stored with local users.
+func NewPasswordAuthenticator(repo Repository, hasher PasswordHasher) Authenticator {
+	return &passwordAuthenticator{repo: repo, hasher: hasher}
+}
+
+func (a *passwordAuthenticator) Name() string {
+	return "password"
+}
+
+func (a *passwordAuthenticator) Authenticate(email, pass string) (*Authentication, error) {
+	user, err := a.repo.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		log.Printf("unable to get user by email, err=%v", err)
+		return nil, err
+	}
+
+	match, needsRehash, err := a.hasher.Verify(user.Pass, pass)
+	if err != nil {
+		log.Printf("unable to verify password for user %s, err=%v", user.UserID, err)
+		return nil, err
+	}
+	if !match {
+		log.Printf("invalid password for user %s", user.UserID)
+		return nil, ErrInvalidCredentials
+	}
+
+	// Upgrade legacy plaintext or weak hashes now that we know the password
+	if needsRehash {
+		a.rehashPassword(user.UserID, pass)
+	}
+	return &Authentication{User: user}, nil
+}
+
+func (a *passwordAuthenticator) rehashPassword(userID, pass string) {
+	hash, err := a.hasher.Hash(pass)
+	if err != nil {
+		log.Printf("unable to rehash password for user %s, err=%v", userID, err)
+		return
+	}
+	if err := a.repo.UpdatePassword(userID, hash); err != nil {
+		log.Printf("unable to store rehashed password for user %s, err=%v", userID, err)
+	}
+}
+
+type directoryAuthenticator struct {
+	name        string
+	directory   Directory
+	roles       auth.RoleMapping
+	defaultRole string
+}
+
+// NewDirectoryAuthenticator checks passwords against an external directory.
+// Users are matched or provisioned by email like single sign-on users, and
+// take the role mapped from their directory groups. When defaultRole is
+// empty, new users none of whose groups are mapped are refused.
+func NewDirectoryAuthenticator(name string, directory Directory, roles auth.RoleMapping, defaultRole string) Authenticator {
+	return &directoryAuthenticator{
+		name:        name,
+		directory:   directory,
+		roles:       roles,
+		defaultRole: defaultRole,
+	}
+}
+
+func (a *directoryAuthenticator) Name() string {
+	return a.name
+}
+
+func (a *directoryAuthenticator) Authenticate(email, pass string) (*Authentication, error) {
+	identity, err := a.directory.Authenticate(email, pass)
+	if errors.Is(err, auth.ErrInvalidCredentials) {
+		return nil, ErrInvalidCredentials
+	} else if err != nil {
+		log.Printf("unable to authenticate against %s, err=%v", a.name, err)
+		return nil, err
+	}
+
+	role, mapped := a.roles.Role(identity.Groups)
+	return &Authentication{
+		Identity:    identity,
+		Role:        role,
+		Mapped:      mapped,
+		DefaultRole: a.defaultRole,
+	}, nil
+}
+
+// authenticate tries each authenticator in order until one accepts the
+// credentials. An unavailable source falls through to the next one, but
+// if every source failed the error is returned rather than reported as
+// bad credentials.
+func (s *Service) authenticate(email, pass string) (*Authentication, error) {
+	var firstErr error
+	rejected := false
+	for _, authenticator := range s.authenticators {
+		result, err := authenticator.Authenticate(email, pass)
+		if err == nil {
+			return result, nil
+		}
+		if errors.Is(err, ErrInvalidCredentials) {
+			rejected = true
+		} else if firstErr == nil {
+			firstErr = err
+		}
+	}
+	if rejected || firstErr == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return nil, firstErr
+}
@@ -1,6 +1,25 @@
 package user
 
-import "backend/services/userd/entity"
+import (
+	"backend/pkg/auth"
+	"backend/services/userd/entity"
+	"context"
+	"time"
+)
+
+// PasswordHasher hashes new passwords and verifies stored ones,
+// reporting when a stored value should be upgraded.
+type PasswordHasher interface {
+	Hash(plain string) (string, error)
+	Verify(stored, plain string) (match bool, needsRehash bool, err error)
+}
+
+// PasswordPolicy lists the rules a new password breaks. HistorySize is how
+// many previous passwords, including the current one, cannot be reused.
+type PasswordPolicy interface {
+	Check(plain string) []string
+	HistorySize() int
+}
 
 type Repository interface {
 	Writer
@@ -9,16 +28,100 @@ type Repository interface {
 
 type Writer interface {
 	CreateUser(user *entity.User) (string, error)
+	UpdatePassword(userID, passHash string) error
+	ReplacePassword(userID, oldHash, newHash string, keep int) error
+	UpdateUserRole(institutionID, userID, role string) error
+	UpdateUserProfile(user *entity.User) error
+	UpdateUserStatus(institutionID, userID, status string) error
+	DeleteUser(institutionID, userID, reassignTo, actorID string) error
+	CreateSession(session *entity.Session) error
+	SwitchSessionInstitution(sessionID, institutionID string) (bool, error)
+	RevokeSession(sessionID string) error
+	RevokeUserSessions(userID string) error
+	RevokeUserSession(sessionID, userID string) (bool, error)
+	TouchSession(sessionID string, seenAt, notBefore time.Time) error
+	CreateInvite(invite *entity.Invite) error
+	ClaimInvite(inviteID string) (bool, error)
+	ReleaseInvite(inviteID string) error
+	MarkEmailVerified(userID string, verifiedAt time.Time) error
+	CreateEmailVerification(verification *entity.EmailVerification) error
+	ClaimEmailVerification(tokenID string) (bool, error)
+	CreatePasswordReset(reset *entity.PasswordReset) error
+	ClaimPasswordResets(userID, tokenID string) (bool, error)
+	SaveTOTP(totp *entity.TOTP) error
+	ConfirmTOTP(userID string, step int64, backupCodeHashes []string) error
+	ClaimTOTPStep(userID string, step int64) (bool, error)
+	DeleteTOTP(userID string) error
+	ReplaceBackupCodes(userID string, codeHashes []string) error
+	UseBackupCode(userID, codeHash string) (bool, error)
+	CreateMFAChallenge(challenge *entity.MFAChallenge) error
+	ClaimMFAChallenge(challengeID string) (bool, error)
+	CreateOIDCLogin(login *entity.OIDCLogin) error
+	ClaimOIDCLogin(stateHash string) (bool, error)
+	CreateRefreshToken(token *entity.RefreshToken) error
+	MarkRefreshTokenUsed(tokenID string) (bool, error)
+	CreateAPIKey(key *entity.APIKey) error
+	RevokeAPIKey(keyID, userID string) (bool, error)
+	RecordEvent(event *auth.Event) error
 }
 
 type Reader interface {
 	GetUserByID(id string) (*entity.User, error)
+	GetInstitutionUser(institutionID, id string) (*entity.User, error)
 	GetUserByEmail(email string) (*entity.User, error)
+	ListUsers(filter entity.UserFilter) ([]*entity.User, error)
+	CountOwnedCompanies(institutionID, userID string) (int, error)
+	GetRole(institutionID, name string) (*entity.Role, error)
+	GetInstitution(institutionID string) (*entity.Institution, error)
+	GetRolePermissions(institutionID, role string) ([]string, error)
+	GetInviteByHash(tokenHash string) (*entity.Invite, error)
+	GetEmailVerificationByHash(tokenHash string) (*entity.EmailVerification, error)
+	GetPasswordResetByHash(tokenHash string) (*entity.PasswordReset, error)
+	GetPasswordHistory(userID string, limit int) ([]string, error)
+	GetTOTP(userID string) (*entity.TOTP, error)
+	GetMFAChallengeByHash(tokenHash string) (*entity.MFAChallenge, error)
+	GetOIDCLogin(stateHash string) (*entity.OIDCLogin, error)
+	GetSession(sessionID string) (*entity.Session, error)
+	ListActiveSessions(userID string, seenAfter time.Time) ([]*entity.Session, error)
+	GetRefreshTokenByHash(tokenHash string) (*entity.RefreshToken, error)
+	ListAPIKeys(userID string) ([]*entity.APIKey, error)
+	ListEvents(filter auth.EventFilter) ([]*auth.Event, error)
 }
 
 type Usecase interface {
-	CreateUser(userName, email, pass, role string) (*entity.User, error)
-	GetUserByID(id string) (*entity.User, error)
-	GetUserByEmail(email string) (*entity.User, error)
-	Login(email, pass string) (string, error)
+	CreateUser(principal *auth.Principal, userName, email, pass, role string) (*entity.User, error)
+	GetUserByID(principal *auth.Principal, id string) (*entity.User, error)
+	GetUserByEmail(principal *auth.Principal, email string) (*entity.User, error)
+	ListUsers(principal *auth.Principal, filter entity.UserFilter, cursor string) ([]*entity.User, string, error)
+	AssignRole(principal *auth.Principal, userID, role string) (*entity.User, error)
+	UpdateProfile(principal *auth.Principal, userID, userName, email string) (*entity.User, error)
+	ChangePassword(principal *auth.Principal, userID, oldPass, newPass string) error
+	DeactivateUser(principal *auth.Principal, userID string) (*entity.User, error)
+	ReactivateUser(principal *auth.Principal, userID string) (*entity.User, error)
+	DeleteUser(principal *auth.Principal, userID, reassignTo string) error
+	UnlockUser(principal *auth.Principal, userID string) error
+	CreateInvite(principal *auth.Principal, email, role string, ttl time.Duration) (*entity.Invite, string, error)
+	RedeemInvite(token, userName, pass string) (*entity.User, error)
+	VerifyEmail(token string) (*entity.User, error)
+	ResendVerification(email string) error
+	ForgotPassword(email string) error
+	ResetPassword(token, newPass string) error
+	Login(email, pass, deviceID string, client entity.Client) (*entity.LoginResult, error)
+	LoginMFA(mfaToken, code string, client entity.Client) (*entity.Tokens, error)
+	StartOIDCLogin(deviceID string) (string, error)
+	FinishOIDCLogin(ctx context.Context, state, code string, client entity.Client) (*entity.LoginResult, error)
+	EnrollTOTP(principal *auth.Principal) (secret, uri string, err error)
+	ConfirmTOTP(principal *auth.Principal, code string) ([]string, error)
+	DisableTOTP(principal *auth.Principal, code string) error
+	RegenerateBackupCodes(principal *auth.Principal, code string) ([]string, error)
+	Refresh(refreshToken, deviceID string) (*entity.Tokens, error)
+	Logout(principal *auth.Principal, refreshToken string) error
+	ListSessions(principal *auth.Principal) ([]*entity.Session, error)
+	RevokeSession(principal *auth.Principal, sessionID string) error
+	RevokeUserSessions(principal *auth.Principal, userID string) error
+	SwitchInstitution(principal *auth.Principal, institutionID string) (*entity.Tokens, error)
+	CreateAPIKey(principal *auth.Principal, name string, scopes []string, expiresAt *time.Time) (*entity.APIKey, string, error)
+	ListAPIKeys(principal *auth.Principal) ([]*entity.APIKey, error)
+	RevokeAPIKey(principal *auth.Principal, keyID string) error
+	ListAuthEvents(principal *auth.Principal, filter auth.EventFilter, cursor string) ([]*auth.Event, string, error)
 }
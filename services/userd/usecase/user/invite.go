@@ -0,0 +1,86 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	defaultInviteTTL = time.Hour * 24 * 7
+	maxInviteTTL     = time.Hour * 24 * 30
+)
+
+var ErrInvalidInvite = errors.New("invalid, expired or already used invite")
+
+// CreateInvite issues a single-use invite for email and role. The raw token
+// is only returned here; the database keeps its hash.
+func (s *Service) CreateInvite(principal *auth.Principal, email, role string, ttl time.Duration) (*entity.Invite, string, error) {
+	if err := s.authz.Authorize(principal, common.UserInvite); err != nil {
+		return nil, "", err
+	}
+	if err := s.checkRoleExists(principal.InstitutionID, role); err != nil {
+		return nil, "", err
+	}
+	if err := s.authorizeRoleGrant(principal, role); err != nil {
+		return nil, "", err
+	}
+
+	if ttl <= 0 {
+		ttl = defaultInviteTTL
+	} else if ttl > maxInviteTTL {
+		ttl = maxInviteTTL
+	}
+
+	invite, token, err := entity.NewInvite(principal.InstitutionID, email, role, principal.UserID, ttl)
+	if err != nil {
+		log.Printf("unable to create invite entity, err=%v", err)
+		return nil, "", err
+	}
+	if err := s.repo.CreateInvite(invite); err != nil {
+		log.Printf("unable to create invite in repository, err=%v", err)
+		return nil, "", err
+	}
+	return invite, token, nil
+}
+
+// RedeemInvite creates the invited account with the password chosen by the invitee.
+func (s *Service) RedeemInvite(token, userName, pass string) (*entity.User, error) {
+	invite, err := s.repo.GetInviteByHash(entity.HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidInvite
+	} else if err != nil {
+		log.Printf("unable to get invite, err=%v", err)
+		return nil, err
+	}
+	if invite.RedeemedAt != nil || time.Now().After(invite.ExpiresAt) {
+		return nil, ErrInvalidInvite
+	}
+	if err := s.checkNewPassword(nil, "pass", pass); err != nil {
+		return nil, err
+	}
+
+	claimed, err := s.repo.ClaimInvite(invite.InviteID)
+	if err != nil {
+		log.Printf("unable to claim invite %s, err=%v", invite.InviteID, err)
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidInvite
+	}
+
+	// The token is handed out by the inviting admin rather than mailed to
+	// invite.Email, so the address still has to be verified
+	user, err := s.createUser(invite.InstitutionID, userName, invite.Email, pass, invite.Role, false)
+	if err != nil {
+		if err := s.repo.ReleaseInvite(invite.InviteID); err != nil {
+			log.Printf("unable to release invite %s, err=%v", invite.InviteID, err)
+		}
+		return nil, err
+	}
+	return user, nil
+}
@@ -0,0 +1,229 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+	"slices"
+)
+
+var (
+	ErrUserNotFound    = errors.New("user not found")
+	ErrUserDeactivated = errors.New("user is deactivated")
+	ErrEmailTaken      = errors.New("email is already used by another user")
+	ErrSelfAction      = errors.New("users cannot deactivate or delete themselves")
+	ErrReassignTarget  = errors.New("owned companies must be reassigned to another active user")
+)
+
+// UpdateProfile changes the name and email of a user. Users may edit their
+// own profile, editing someone else's needs user:update. API keys always
+// need it, even for their owner's profile.
+func (s *Service) UpdateProfile(principal *auth.Principal, userID, userName, email string) (*entity.User, error) {
+	if principal == nil || principal.UserID != userID || principal.IsAPIKey() {
+		if err := s.authz.Authorize(principal, common.UserUpdate); err != nil {
+			return nil, err
+		}
+	}
+
+	var user *entity.User
+	var err error
+	if principal.UserID == userID {
+		user, err = s.getUser(userID)
+	} else {
+		user, err = s.getManagedUser(principal, userID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	emailChanged := user.Email != email
+	if err := user.UpdateProfile(userName, email); err != nil {
+		log.Printf("unable to update user entity, err=%v", err)
+		return nil, err
+	}
+
+	existing, err := s.repo.GetUserByEmail(email)
+	if err == nil && existing.UserID != userID {
+		return nil, ErrEmailTaken
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("unable to get user by email, err=%v", err)
+		return nil, err
+	}
+
+	if err := s.repo.UpdateUserProfile(user); err != nil {
+		log.Printf("unable to update user %s in repository, err=%v", userID, err)
+		return nil, err
+	}
+	if emailChanged {
+		s.sendVerification(user)
+	}
+	return user, nil
+}
+
+// ChangePassword lets users replace their own password after proving they know the current one.
+func (s *Service) ChangePassword(principal *auth.Principal, userID, oldPass, newPass string) error {
+	if err := requireSession(principal); err != nil {
+		return err
+	}
+	if principal.UserID != userID {
+		return auth.ErrForbidden
+	}
+
+	user, err := s.getUser(userID)
+	if err != nil {
+		return err
+	}
+	match, _, err := s.hasher.Verify(user.Pass, oldPass)
+	if err != nil {
+		log.Printf("unable to verify password for user %s, err=%v", userID, err)
+		return err
+	}
+	if !match {
+		return ErrInvalidCredentials
+	}
+	if err := s.checkNewPassword(user, "new_pass", newPass); err != nil {
+		return err
+	}
+
+	hash, err := s.hasher.Hash(newPass)
+	if err != nil {
+		log.Printf("unable to hash password, err=%v", err)
+		return err
+	}
+	return s.replacePassword(user, hash)
+}
+
+// DeactivateUser blocks login for a user and revokes every session they have.
+func (s *Service) DeactivateUser(principal *auth.Principal, userID string) (*entity.User, error) {
+	if err := s.authz.Authorize(principal, common.UserDeactivate); err != nil {
+		return nil, err
+	}
+	if principal.UserID == userID {
+		return nil, ErrSelfAction
+	}
+
+	user, err := s.getManagedUser(principal, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateUserStatus(principal.InstitutionID, userID, entity.UserDeactivated); err != nil {
+		log.Printf("unable to deactivate user %s, err=%v", userID, err)
+		return nil, err
+	}
+	if err := s.repo.RevokeUserSessions(userID); err != nil {
+		log.Printf("unable to revoke sessions of user %s, err=%v", userID, err)
+		return nil, err
+	}
+	s.recordTokenRevoked(principal, userID, "all sessions: user deactivated")
+
+	user.Status = entity.UserDeactivated
+	return user, nil
+}
+
+func (s *Service) ReactivateUser(principal *auth.Principal, userID string) (*entity.User, error) {
+	if err := s.authz.Authorize(principal, common.UserDeactivate); err != nil {
+		return nil, err
+	}
+
+	user, err := s.getManagedUser(principal, userID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.UpdateUserStatus(principal.InstitutionID, userID, entity.UserActive); err != nil {
+		log.Printf("unable to reactivate user %s, err=%v", userID, err)
+		return nil, err
+	}
+
+	user.Status = entity.UserActive
+	return user, nil
+}
+
+// DeleteUser removes a user for good. Companies they own are handed to
+// reassignTo, which is required if there are any.
+func (s *Service) DeleteUser(principal *auth.Principal, userID, reassignTo string) error {
+	if err := s.authz.Authorize(principal, common.UserDelete); err != nil {
+		return err
+	}
+	if principal.UserID == userID {
+		return ErrSelfAction
+	}
+
+	if _, err := s.getManagedUser(principal, userID); err != nil {
+		return err
+	}
+
+	owned, err := s.repo.CountOwnedCompanies(principal.InstitutionID, userID)
+	if err != nil {
+		log.Printf("unable to count companies owned by user %s, err=%v", userID, err)
+		return err
+	}
+	if owned > 0 || reassignTo != "" {
+		if reassignTo == "" || reassignTo == userID {
+			return ErrReassignTarget
+		}
+		target, err := s.repo.GetInstitutionUser(principal.InstitutionID, reassignTo)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrReassignTarget
+		} else if err != nil {
+			log.Printf("unable to get user by id, err=%v", err)
+			return err
+		}
+		if !target.IsActive() {
+			return ErrReassignTarget
+		}
+	}
+
+	if err := s.repo.DeleteUser(principal.InstitutionID, userID, reassignTo, principal.UserID); err != nil {
+		log.Printf("unable to delete user %s, err=%v", userID, err)
+		return err
+	}
+	return nil
+}
+
+func (s *Service) getUser(userID string) (*entity.User, error) {
+	user, err := s.repo.GetUserByID(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		log.Printf("unable to get user by id, err=%v", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// getInstitutionUser is getUser for user IDs supplied by callers, which
+// must not reach accounts of other institutions.
+func (s *Service) getInstitutionUser(institutionID, userID string) (*entity.User, error) {
+	user, err := s.repo.GetInstitutionUser(institutionID, userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
+		log.Printf("unable to get user by id, err=%v", err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// getManagedUser is getInstitutionUser for admin actions on the account.
+// Super-admins live in ordinary institutions, so accounts whose role holds
+// institution:manage can only be changed by another super-admin.
+func (s *Service) getManagedUser(principal *auth.Principal, userID string) (*entity.User, error) {
+	user, err := s.getInstitutionUser(principal.InstitutionID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	permissions, err := s.repo.GetRolePermissions(user.InstitutionID, user.Role)
+	if err != nil {
+		log.Printf("unable to get permissions of role '%s', err=%v", user.Role, err)
+		return nil, err
+	}
+	if slices.Contains(permissions, common.InstitutionManage) {
+		if err := s.authz.Authorize(principal, common.InstitutionManage); err != nil {
+			return nil, err
+		}
+	}
+	return user, nil
+}
@@ -0,0 +1,77 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"encoding/base64"
+	"errors"
+	"log"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// ListUsers returns one page of the user directory and the cursor of the
+// next page, which is empty on the last page.
+func (s *Service) ListUsers(principal *auth.Principal, filter entity.UserFilter, cursor string) ([]*entity.User, string, error) {
+	if err := s.authz.Authorize(principal, common.UserRead); err != nil {
+		return nil, "", err
+	}
+	filter.InstitutionID = principal.InstitutionID
+
+	if cursor != "" {
+		after, err := decodeCursor(cursor)
+		if err != nil {
+			return nil, "", err
+		}
+		filter.After = after
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	} else if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+	pageSize := filter.Limit
+
+	// Fetch one extra row to know whether there is a next page
+	filter.Limit++
+	users, err := s.repo.ListUsers(filter)
+	if err != nil {
+		log.Printf("unable to list users, err=%v", err)
+		return nil, "", err
+	}
+
+	if len(users) <= pageSize {
+		return users, "", nil
+	}
+	users = users[:pageSize]
+	last := users[len(users)-1]
+	return users, encodeCursor(last.CreatedAt, last.UserID), nil
+}
+
+func encodeCursor(createdAt time.Time, userID string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt.UTC().Format(time.RFC3339Nano) + "|" + userID))
+}
+
+func decodeCursor(cursor string) (*entity.UserCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	createdAt, userID, ok := strings.Cut(string(raw), "|")
+	if !ok || userID == "" {
+		return nil, ErrInvalidCursor
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+	return &entity.UserCursor{CreatedAt: t, UserID: userID}, nil
+}
@@ -0,0 +1,285 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/totp"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const (
+	totpIssuer      = "Placement Platform"
+	mfaChallengeTTL = 5 * time.Minute
+	backupCodeCount = 10
+)
+
+var (
+	ErrInvalidMFAChallenge = errors.New("invalid, expired or already used MFA challenge")
+	ErrInvalidMFACode      = errors.New("invalid MFA code")
+	ErrMFAAlreadyEnabled   = errors.New("two-factor authentication is already enabled")
+	ErrMFANotEnabled       = errors.New("two-factor authentication is not enabled")
+)
+
+func mfaThrottleKey(userID string) string {
+	return "mfa:" + userID
+}
+
+// EnrollTOTP starts an authenticator app enrollment for the caller. It does
+// not take effect until ConfirmTOTP.
+func (s *Service) EnrollTOTP(principal *auth.Principal) (secret, uri string, err error) {
+	if err := requireSession(principal); err != nil {
+		return "", "", err
+	}
+
+	existing, err := s.repo.GetTOTP(principal.UserID)
+	if err == nil && existing.IsConfirmed() {
+		return "", "", ErrMFAAlreadyEnabled
+	} else if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		log.Printf("unable to get TOTP enrollment of user %s, err=%v", principal.UserID, err)
+		return "", "", err
+	}
+
+	secret, err = totp.GenerateSecret()
+	if err != nil {
+		log.Printf("unable to generate TOTP secret, err=%v", err)
+		return "", "", err
+	}
+	if err := s.repo.SaveTOTP(&entity.TOTP{
+		UserID:       principal.UserID,
+		EnrollmentID: uuid.NewString(),
+		Secret:       secret,
+		CreatedAt:    time.Now(),
+	}); err != nil {
+		log.Printf("unable to store TOTP enrollment of user %s, err=%v", principal.UserID, err)
+		return "", "", err
+	}
+	return secret, totp.ProvisioningURI(totpIssuer, principal.Email, secret), nil
+}
+
+// ConfirmTOTP enables two-factor authentication once the caller proves their
+// app generates valid codes, and returns their backup codes. This is the
+// only time the backup codes are shown.
+func (s *Service) ConfirmTOTP(principal *auth.Principal, code string) ([]string, error) {
+	if err := requireSession(principal); err != nil {
+		return nil, err
+	}
+
+	enrollment, err := s.repo.GetTOTP(principal.UserID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrMFANotEnabled
+	} else if err != nil {
+		log.Printf("unable to get TOTP enrollment of user %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	if enrollment.IsConfirmed() {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	step, ok, err := totp.Validate(enrollment.Secret, code, time.Now(), enrollment.LastUsedStep)
+	if err != nil {
+		log.Printf("unable to validate TOTP code of user %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	if !ok {
+		return nil, ErrInvalidMFACode
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ConfirmTOTP(principal.UserID, step, hashes); err != nil {
+		log.Printf("unable to confirm TOTP enrollment of user %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP turns two-factor authentication off after checking a current
+// code or backup code.
+func (s *Service) DisableTOTP(principal *auth.Principal, code string) error {
+	if err := requireSession(principal); err != nil {
+		return err
+	}
+	if err := s.verifyMFACode(principal.UserID, code, principal.IP); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteTOTP(principal.UserID); err != nil {
+		log.Printf("unable to delete TOTP enrollment of user %s, err=%v", principal.UserID, err)
+		return err
+	}
+	return nil
+}
+
+// RegenerateBackupCodes replaces the caller's backup codes after checking a
+// current code or backup code.
+func (s *Service) RegenerateBackupCodes(principal *auth.Principal, code string) ([]string, error) {
+	if err := requireSession(principal); err != nil {
+		return nil, err
+	}
+	if err := s.verifyMFACode(principal.UserID, code, principal.IP); err != nil {
+		return nil, err
+	}
+
+	codes, hashes, err := newBackupCodes()
+	if err != nil {
+		return nil, err
+	}
+	if err := s.repo.ReplaceBackupCodes(principal.UserID, hashes); err != nil {
+		log.Printf("unable to store backup codes of user %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	return codes, nil
+}
+
+// LoginMFA completes a login started by Login with a TOTP code or a backup code.
+func (s *Service) LoginMFA(mfaToken, code string, client entity.Client) (*entity.Tokens, error) {
+	challenge, err := s.repo.GetMFAChallengeByHash(entity.HashToken(mfaToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidMFAChallenge
+	} else if err != nil {
+		log.Printf("unable to get MFA challenge, err=%v", err)
+		return nil, err
+	}
+	if challenge.UsedAt != nil || time.Now().After(challenge.ExpiresAt) {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	if err := s.verifyMFACode(challenge.UserID, code, client.IP); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			s.recordEvent(auth.Event{
+				Type:      auth.EventLoginFailed,
+				SubjectID: challenge.UserID,
+				IP:        client.IP,
+				Detail:    err.Error(),
+			})
+		}
+		return nil, err
+	}
+
+	claimed, err := s.repo.ClaimMFAChallenge(challenge.ChallengeID)
+	if err != nil {
+		log.Printf("unable to claim MFA challenge %s, err=%v", challenge.ChallengeID, err)
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidMFAChallenge
+	}
+
+	user, err := s.getUser(challenge.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLoginAllowed(user); err != nil {
+		s.recordLoginRefused(user, client, err)
+		return nil, err
+	}
+	return s.startSession(user, challenge.DeviceID, true, client)
+}
+
+// verifyMFACode accepts a TOTP code or an unused backup code of a user with
+// confirmed two-factor authentication. Failures count towards a lockout
+// like failed logins do; the attempt is counted before the code is checked
+// so concurrent guesses cannot all slip past the limits.
+func (s *Service) verifyMFACode(userID, code, ip string) error {
+	key := mfaThrottleKey(userID)
+	locked, err := s.throttle.Attempt(key, true)
+	if err != nil {
+		return err
+	}
+
+	ok, err := s.matchMFACode(userID, code)
+	if err != nil {
+		if err := s.throttle.Release(key, locked); err != nil {
+			log.Printf("unable to release MFA attempt for user %s, err=%v", userID, err)
+		}
+		return err
+	}
+	if !ok {
+		log.Printf("invalid MFA code for user %s", userID)
+		if locked {
+			s.recordEvent(auth.Event{
+				Type:      auth.EventAccountLocked,
+				SubjectID: userID,
+				IP:        ip,
+				Detail:    "too many invalid second factor codes",
+			})
+		}
+		return ErrInvalidMFACode
+	}
+
+	if err := s.throttle.Reset(key); err != nil {
+		log.Printf("unable to reset failed MFA codes for user %s, err=%v", userID, err)
+	}
+	return nil
+}
+
+// matchMFACode reports whether code is valid for a user with confirmed
+// two-factor authentication.
+func (s *Service) matchMFACode(userID, code string) (bool, error) {
+	enrollment, err := s.repo.GetTOTP(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, ErrMFANotEnabled
+	} else if err != nil {
+		log.Printf("unable to get TOTP enrollment of user %s, err=%v", userID, err)
+		return false, err
+	}
+	if !enrollment.IsConfirmed() {
+		return false, ErrMFANotEnabled
+	}
+	return s.checkMFACode(enrollment, code)
+}
+
+func (s *Service) checkMFACode(enrollment *entity.TOTP, code string) (bool, error) {
+	step, ok, err := totp.Validate(enrollment.Secret, code, time.Now(), enrollment.LastUsedStep)
+	if err != nil {
+		log.Printf("unable to validate TOTP code of user %s, err=%v", enrollment.UserID, err)
+		return false, err
+	}
+	if ok {
+		// Another request may have used the same code in the meantime
+		claimed, err := s.repo.ClaimTOTPStep(enrollment.UserID, step)
+		if err != nil {
+			log.Printf("unable to record TOTP step of user %s, err=%v", enrollment.UserID, err)
+			return false, err
+		}
+		return claimed, nil
+	}
+
+	used, err := s.repo.UseBackupCode(enrollment.UserID, entity.HashBackupCode(code))
+	if err != nil {
+		log.Printf("unable to use backup code of user %s, err=%v", enrollment.UserID, err)
+		return false, err
+	}
+	return used, nil
+}
+
+func newBackupCodes() ([]string, []string, error) {
+	codes, err := entity.NewBackupCodes(backupCodeCount)
+	if err != nil {
+		log.Printf("unable to generate backup codes, err=%v", err)
+		return nil, nil, err
+	}
+	hashes := make([]string, len(codes))
+	for i, code := range codes {
+		hashes[i] = entity.HashBackupCode(code)
+	}
+	return codes, hashes, nil
+}
+
+// hasMFA reports whether a user has to pass a second factor to log in.
+func (s *Service) hasMFA(userID string) (bool, error) {
+	enrollment, err := s.repo.GetTOTP(userID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return false, nil
+	} else if err != nil {
+		log.Printf("unable to get TOTP enrollment of user %s, err=%v", userID, err)
+		return false, err
+	}
+	return enrollment.IsConfirmed(), nil
+}
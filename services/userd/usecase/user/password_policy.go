@@ -0,0 +1,93 @@
+package user
+
+import (
+	"backend/services/userd/entity"
+	"fmt"
+	"log"
+	"slices"
+	"strings"
+)
+
+// ValidationError reports what is wrong with individual request fields,
+// keyed by their JSON names.
+type ValidationError struct {
+	Fields map[string][]string
+}
+
+func newValidationError(field string, problems []string) *ValidationError {
+	return &ValidationError{Fields: map[string][]string{field: problems}}
+}
+
+func (e *ValidationError) Error() string {
+	fields := make([]string, 0, len(e.Fields))
+	for field := range e.Fields {
+		fields = append(fields, field)
+	}
+	slices.Sort(fields)
+
+	details := make([]string, 0, len(fields))
+	for _, field := range fields {
+		details = append(details, fmt.Sprintf("%s %s", field, strings.Join(e.Fields[field], ", ")))
+	}
+	return "invalid request: " + strings.Join(details, "; ")
+}
+
+// checkNewPassword applies the password policy to a password chosen for
+// user, which is nil for accounts that do not exist yet. field names the
+// request field the password came from.
+func (s *Service) checkNewPassword(user *entity.User, field, plain string) error {
+	problems := s.passwords.Check(plain)
+	if len(problems) == 0 && user != nil {
+		reused, err := s.isRecentPassword(user, plain)
+		if err != nil {
+			return err
+		}
+		if reused {
+			problems = append(problems, fmt.Sprintf("cannot be one of your last %d passwords", s.passwords.HistorySize()))
+		}
+	}
+	if len(problems) > 0 {
+		return newValidationError(field, problems)
+	}
+	return nil
+}
+
+// isRecentPassword reports whether plain is the current password of user
+// or one of the previous ones the policy remembers.
+func (s *Service) isRecentPassword(user *entity.User, plain string) (bool, error) {
+	size := s.passwords.HistorySize()
+	if size == 0 {
+		return false, nil
+	}
+
+	hashes := []string{user.Pass}
+	if size > 1 {
+		previous, err := s.repo.GetPasswordHistory(user.UserID, size-1)
+		if err != nil {
+			log.Printf("unable to get password history of user %s, err=%v", user.UserID, err)
+			return false, err
+		}
+		hashes = append(hashes, previous...)
+	}
+	for _, hash := range hashes {
+		match, _, err := s.hasher.Verify(hash, plain)
+		if err != nil {
+			log.Printf("unable to verify password history of user %s, err=%v", user.UserID, err)
+			return false, err
+		}
+		if match {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// replacePassword stores the hash of a new password for user, remembering
+// the old one for the reuse check.
+func (s *Service) replacePassword(user *entity.User, hash string) error {
+	if err := s.repo.ReplacePassword(user.UserID, user.Pass, hash, max(s.passwords.HistorySize()-1, 0)); err != nil {
+		log.Printf("unable to update password for user %s, err=%v", user.UserID, err)
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/mailer"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const passwordResetTTL = time.Hour
+
+var ErrInvalidResetToken = errors.New("invalid, expired or already used reset token")
+
+// ForgotPassword emails a reset token to the owner of email. It reports
+// success for unknown or deactivated accounts too, so callers cannot probe
+// which addresses are registered.
+func (s *Service) ForgotPassword(email string) error {
+	user, err := s.repo.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		log.Printf("unable to get user by email, err=%v", err)
+		return err
+	}
+	if !user.IsActive() {
+		return nil
+	}
+
+	reset, token, err := entity.NewPasswordReset(user.UserID, passwordResetTTL)
+	if err != nil {
+		log.Printf("unable to create password reset entity, err=%v", err)
+		return err
+	}
+	if err := s.repo.CreatePasswordReset(reset); err != nil {
+		log.Printf("unable to create password reset in repository, err=%v", err)
+		return err
+	}
+
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Reset your password",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Use this token to choose a new password. It expires in %s and works once:\n\n"+
+			"%s\n\n"+
+			"If you did not ask for a password reset you can ignore this email.",
+			user.UserName, passwordResetTTL, token),
+	}); err != nil {
+		log.Printf("unable to send password reset email to user %s, err=%v", user.UserID, err)
+	}
+	return nil
+}
+
+// ResetPassword sets a new password using an emailed reset token and signs
+// the user out everywhere.
+func (s *Service) ResetPassword(token, newPass string) error {
+	reset, err := s.repo.GetPasswordResetByHash(entity.HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrInvalidResetToken
+	} else if err != nil {
+		log.Printf("unable to get password reset, err=%v", err)
+		return err
+	}
+	if reset.UsedAt != nil || time.Now().After(reset.ExpiresAt) {
+		return ErrInvalidResetToken
+	}
+
+	// Checked before claiming, so a rejected password does not use up the token
+	user, err := s.getUser(reset.UserID)
+	if err != nil {
+		return err
+	}
+	if err := s.checkNewPassword(user, "new_pass", newPass); err != nil {
+		return err
+	}
+	hash, err := s.hasher.Hash(newPass)
+	if err != nil {
+		log.Printf("unable to hash password, err=%v", err)
+		return err
+	}
+
+	claimed, err := s.repo.ClaimPasswordResets(reset.UserID, reset.TokenID)
+	if err != nil {
+		log.Printf("unable to claim password reset %s, err=%v", reset.TokenID, err)
+		return err
+	}
+	if !claimed {
+		return ErrInvalidResetToken
+	}
+
+	if err := s.replacePassword(user, hash); err != nil {
+		return err
+	}
+	if err := s.repo.RevokeUserSessions(reset.UserID); err != nil {
+		log.Printf("unable to revoke sessions of user %s, err=%v", reset.UserID, err)
+		return err
+	}
+	s.recordEvent(auth.Event{
+		Type:      auth.EventTokenRevoked,
+		SubjectID: reset.UserID,
+		Detail:    "all sessions: password reset",
+	})
+	return nil
+}
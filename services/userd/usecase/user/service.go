@@ -1,84 +1,390 @@
 package user
 
 import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/pkg/mailer"
+	"backend/pkg/throttle"
 	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"fmt"
 	"log"
+	"slices"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
 )
 
+// ErrInvalidCredentials is returned when the email or password does not match.
+var ErrInvalidCredentials = errors.New("invalid email or password")
+
+// ErrUnknownRole is returned when a user is given a role that does not exist.
+var ErrUnknownRole = errors.New("invalid role")
+
 type Service struct {
-	repo      Repository
-	JWTSecret string
+	repo           Repository
+	hasher         PasswordHasher
+	passwords      PasswordPolicy
+	keys           auth.KeyStore
+	revocations    auth.RevocationList
+	authz          *auth.Authorizer
+	mailer         mailer.Mailer
+	throttle       *throttle.Throttler
+	sso            *SSO
+	authenticators []Authenticator
 }
 
-func NewService(repo Repository, jwt string) *Service {
+// NewService wires the user usecases. sso may be nil when single sign-on is
+// not configured. Without authenticators logins check local passwords only.
+func NewService(repo Repository, hasher PasswordHasher, passwords PasswordPolicy, keys auth.KeyStore, revocations auth.RevocationList, authz *auth.Authorizer, mailer mailer.Mailer, throttle *throttle.Throttler, sso *SSO, authenticators []Authenticator) *Service {
+	if len(authenticators) == 0 {
+		authenticators = []Authenticator{NewPasswordAuthenticator(repo, hasher)}
+	}
 	return &Service{
-		repo:      repo,
-		JWTSecret: jwt,
+		repo:           repo,
+		hasher:         hasher,
+		passwords:      passwords,
+		keys:           keys,
+		revocations:    revocations,
+		authz:          authz,
+		mailer:         mailer,
+		throttle:       throttle,
+		sso:            sso,
+		authenticators: authenticators,
+	}
+}
+
+func (s *Service) CreateUser(principal *auth.Principal, userName, email, pass, role string) (*entity.User, error) {
+	if err := s.authz.Authorize(principal, common.UserCreate); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRoleGrant(principal, role); err != nil {
+		return nil, err
+	}
+	if err := s.checkNewPassword(nil, "pass", pass); err != nil {
+		return nil, err
+	}
+	return s.createUser(principal.InstitutionID, userName, email, pass, role, false)
+}
+
+// CreateAdmin creates an admin of an institution without a caller, for
+// bootstrapping the first account from the command line. It is not exposed
+// over HTTP. The operator vouches for the address, so it starts out
+// verified. Super-admins manage every institution.
+func (s *Service) CreateAdmin(institutionID, userName, email, pass string, super bool) (*entity.User, error) {
+	if err := s.checkNewPassword(nil, "pass", pass); err != nil {
+		return nil, err
+	}
+	if _, err := s.repo.GetInstitution(institutionID); err != nil {
+		log.Printf("unable to get institution %s, err=%v", institutionID, err)
+		return nil, err
 	}
+	role := common.RoleAdmin
+	if super {
+		role = common.RoleSuperAdmin
+	}
+	return s.createUser(institutionID, userName, email, pass, role, true)
 }
 
-func (s *Service) CreateUser(userName, email, pass, role string) (*entity.User, error) {
-	user, err := entity.NewUser(userName, email, pass, role)
+// createUser stores a new user and, unless emailVerified, emails them a
+// verification token.
+func (s *Service) createUser(institutionID, userName, email, pass, role string, emailVerified bool) (*entity.User, error) {
+	user, err := entity.NewUser(institutionID, userName, email, pass, role)
 	if err != nil {
 		log.Printf("unable to create user entity, err=%v", err)
 		return nil, err
 	}
+	if emailVerified {
+		user.MarkEmailVerified()
+	}
+	if err := s.checkRoleExists(institutionID, role); err != nil {
+		return nil, err
+	}
+	user.Pass, err = s.hasher.Hash(user.Pass)
+	if err != nil {
+		log.Printf("unable to hash password, err=%v", err)
+		return nil, err
+	}
 	userID, err := s.repo.CreateUser(user)
 	if err != nil {
 		log.Printf("unable to create user in repository, err=%v", err)
 		return nil, err
 	}
 	user.UserID = userID
+
+	if !user.IsEmailVerified() {
+		s.sendVerification(user)
+	}
 	return user, nil
 }
 
-func (s *Service) GetUserByID(id string) (*entity.User, error) {
-	user, err := s.repo.GetUserByID(id)
-	if err != nil {
+func (s *Service) GetUserByID(principal *auth.Principal, id string) (*entity.User, error) {
+	// Users may always read their own account, others only within the
+	// institution they are working in
+	if principal != nil && principal.UserID == id {
+		user, err := s.repo.GetUserByID(id)
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrUserNotFound
+		} else if err != nil {
+			log.Printf("unable to get user by id, err=%v", err)
+			return nil, err
+		}
+		return user, nil
+	}
+	if err := s.authz.Authorize(principal, common.UserRead); err != nil {
+		return nil, err
+	}
+
+	user, err := s.repo.GetInstitutionUser(principal.InstitutionID, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrUserNotFound
+	} else if err != nil {
 		log.Printf("unable to get user by id, err=%v", err)
 		return nil, err
 	}
 	return user, nil
 }
 
-func (s *Service) GetUserByEmail(email string) (*entity.User, error) {
+func (s *Service) GetUserByEmail(principal *auth.Principal, email string) (*entity.User, error) {
+	self := principal != nil && principal.Email == email
+	if !self {
+		if err := s.authz.Authorize(principal, common.UserRead); err != nil {
+			return nil, err
+		}
+	}
+
 	user, err := s.repo.GetUserByEmail(email)
 	if err != nil {
 		log.Printf("unable to get user by email, err=%v", err)
 		return nil, err
 	}
+	// Emails are unique across institutions, but others' users must not show
+	if !self && user.InstitutionID != principal.InstitutionID {
+		return nil, sql.ErrNoRows
+	}
 	return user, nil
 }
 
-func (s *Service) Login(email, pass string) (string, error) {
-	user, err := s.repo.GetUserByEmail(email)
+// AssignRole changes the role of a user. The user's sessions are revoked so
+// tokens carrying the old role stop working immediately.
+func (s *Service) AssignRole(principal *auth.Principal, userID, role string) (*entity.User, error) {
+	if err := s.authz.Authorize(principal, common.RoleManage); err != nil {
+		return nil, err
+	}
+	if err := s.checkRoleExists(principal.InstitutionID, role); err != nil {
+		return nil, err
+	}
+	if err := s.authorizeRoleGrant(principal, role); err != nil {
+		return nil, err
+	}
+
+	previous, err := s.getManagedUser(principal, userID)
 	if err != nil {
-		log.Printf("unable to get user by email, err=%v", err)
-		return "", err
+		return nil, err
+	}
+	if err := s.repo.UpdateUserRole(principal.InstitutionID, userID, role); err != nil {
+		log.Printf("unable to update role of user %s, err=%v", userID, err)
+		return nil, err
 	}
+	if err := s.repo.RevokeUserSessions(userID); err != nil {
+		log.Printf("unable to revoke sessions of user %s, err=%v", userID, err)
+		return nil, err
+	}
+	s.recordEvent(auth.Event{
+		Type:          auth.EventRoleChanged,
+		InstitutionID: principal.InstitutionID,
+		ActorID:       principal.UserID,
+		SubjectID:     userID,
+		IP:            principal.IP,
+		Detail:        fmt.Sprintf("'%s' to '%s'", previous.Role, role),
+	})
 
-	if user.Pass != pass {
-		log.Printf("invalid password for user %s", user.UserName)
-		return "", err
+	user, err := s.repo.GetUserByID(userID)
+	if err != nil {
+		log.Printf("unable to get user by id, err=%v", err)
+		return nil, err
 	}
+	return user, nil
+}
 
-	return s.generateJWT(user.UserID, user.Email, user.UserName, user.Role)
+// authorizeRoleGrant decides which roles a caller may hand out. Roles that
+// manage institutions are only granted by super-admins. Otherwise callers
+// with role:manage may grant any role; everyone else only roles with
+// strictly fewer permissions than their own, so managers can create
+// officers but neither managers nor admins.
+func (s *Service) authorizeRoleGrant(principal *auth.Principal, role string) error {
+	rolePermissions, err := s.repo.GetRolePermissions(principal.InstitutionID, role)
+	if err != nil {
+		log.Printf("unable to get permissions of role '%s', err=%v", role, err)
+		return err
+	}
+	if slices.Contains(rolePermissions, common.InstitutionManage) {
+		return s.authz.Authorize(principal, common.InstitutionManage)
+	}
+
+	callerPermissions, err := s.repo.GetRolePermissions(principal.InstitutionID, principal.Role)
+	if err != nil {
+		log.Printf("unable to get permissions of role '%s', err=%v", principal.Role, err)
+		return err
+	}
+	if slices.Contains(callerPermissions, common.RoleManage) {
+		return nil
+	}
+
+	for _, permission := range rolePermissions {
+		if !slices.Contains(callerPermissions, permission) {
+			return s.denyRoleGrant(principal, role)
+		}
+	}
+	if len(rolePermissions) == len(callerPermissions) {
+		return s.denyRoleGrant(principal, role)
+	}
+	return nil
 }
 
-func (s *Service) generateJWT(ID, name, email, role string) (string, error) {
-	claims := jwt.MapClaims{
-		"user_id": ID,
-		"user_name": name,
-		"email":  email,
-		"role":     role,
-		"exp":      time.Now().Add(time.Hour * 1).Unix(),
-		"iat":      time.Now().Unix(),
+func (s *Service) denyRoleGrant(principal *auth.Principal, role string) error {
+	log.Printf("user %s with role '%s' denied granting role '%s'", principal.UserID, principal.Role, role)
+	s.recordEvent(auth.Event{
+		Type:          auth.EventPermissionDenied,
+		InstitutionID: principal.InstitutionID,
+		ActorID:       principal.UserID,
+		IP:            principal.IP,
+		Detail:        fmt.Sprintf("granting role '%s': role '%s' may only grant roles with fewer permissions", role, principal.Role),
+	})
+	return auth.ErrForbidden
+}
+
+// checkRoleExists accepts the shared roles and the custom roles of the
+// institution the user belongs to.
+func (s *Service) checkRoleExists(institutionID, role string) error {
+	_, err := s.repo.GetRole(institutionID, role)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrUnknownRole
+	} else if err != nil {
+		log.Printf("unable to get role, err=%v", err)
+		return err
 	}
+	return nil
+}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+// Login checks the password of a user against each configured
+// authenticator in turn. Users with two-factor authentication get an MFA
+// challenge to complete through LoginMFA instead of tokens.
+func (s *Service) Login(email, pass, deviceID string, client entity.Client) (*entity.LoginResult, error) {
+	attempt, err := s.beginLoginAttempt(email, client.IP)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.authenticate(email, pass)
+	if errors.Is(err, ErrInvalidCredentials) {
+		// Unknown emails count too, so lockouts do not reveal which accounts exist
+		s.recordLoginFailure(attempt)
+		return nil, err
+	} else if err != nil {
+		s.abandonLoginAttempt(attempt)
+		return nil, err
+	}
+	s.recordLoginSuccess(attempt)
+
+	user := result.User
+	if result.Identity != nil {
+		user, err = s.syncExternalUser(result.Identity.Email, result.Identity.Name, result.Role, result.Mapped, result.DefaultRole)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := s.checkLoginAllowed(user); err != nil {
+		s.recordLoginRefused(user, client, err)
+		return nil, err
+	}
+	return s.completeLogin(user, deviceID, client)
+}
+
+// completeLogin starts a session for an authenticated user, or hands out an
+// MFA challenge first if they have a second factor.
+func (s *Service) completeLogin(user *entity.User, deviceID string, client entity.Client) (*entity.LoginResult, error) {
+	hasMFA, err := s.hasMFA(user.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if hasMFA {
+		challenge, token, err := entity.NewMFAChallenge(user.UserID, deviceID, mfaChallengeTTL)
+		if err != nil {
+			log.Printf("unable to create MFA challenge entity, err=%v", err)
+			return nil, err
+		}
+		if err := s.repo.CreateMFAChallenge(challenge); err != nil {
+			log.Printf("unable to create MFA challenge in repository, err=%v", err)
+			return nil, err
+		}
+		return &entity.LoginResult{MFAToken: token, MFAExpiresAt: challenge.ExpiresAt}, nil
+	}
+
+	tokens, err := s.startSession(user, deviceID, false, client)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.LoginResult{Tokens: tokens}, nil
+}
+
+// startSession completes a login, after any second factor, and records it.
+func (s *Service) startSession(user *entity.User, deviceID string, mfa bool, client entity.Client) (*entity.Tokens, error) {
+	session := entity.NewSession(user.UserID, user.InstitutionID, deviceID, client)
+	session.MFA = mfa
+	if err := s.repo.CreateSession(session); err != nil {
+		log.Printf("unable to create session, err=%v", err)
+		return nil, err
+	}
+	tokens, err := s.issueTokens(user, session)
+	if err != nil {
+		return nil, err
+	}
+
+	detail := "session " + session.SessionID
+	if mfa {
+		detail += " with second factor"
+	}
+	s.recordEvent(auth.Event{
+		Type:          auth.EventLoginSucceeded,
+		InstitutionID: user.InstitutionID,
+		ActorID:       user.UserID,
+		SubjectID:     user.UserID,
+		IP:            client.IP,
+		Detail:        detail,
+	})
+	return tokens, nil
+}
+
+// recordLoginRefused logs a login with valid credentials that was refused,
+// for example because the account is deactivated.
+func (s *Service) recordLoginRefused(user *entity.User, client entity.Client, err error) {
+	s.recordEvent(auth.Event{
+		Type:          auth.EventLoginFailed,
+		InstitutionID: user.InstitutionID,
+		SubjectID:     user.UserID,
+		Email:         user.Email,
+		IP:            client.IP,
+		Detail:        err.Error(),
+	})
+}
+
+func (s *Service) generateJWT(user *entity.User, session *entity.Session, expiresAt time.Time) (string, error) {
+	claims := jwt.MapClaims{
+		"jti":            uuid.NewString(),
+		"sid":            session.SessionID,
+		"user_id":        user.UserID,
+		"user_name":      user.UserName,
+		"email":          user.Email,
+		"email_verified": user.IsEmailVerified(),
+		"role":           user.Role,
+		"institution_id": session.InstitutionID,
+		"mfa":            session.MFA,
+		"exp":            expiresAt.Unix(),
+		"iat":            time.Now().Unix(),
+	}
 
-	return token.SignedString([]byte(s.JWTSecret))
+	return auth.SignToken(s.keys, claims)
 }
@@ -0,0 +1,260 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"log"
+	"time"
+)
+
+const (
+	accessTokenTTL  = time.Hour * 1
+	refreshTokenTTL = time.Hour * 24 * 30
+)
+
+var (
+	ErrInvalidRefreshToken = errors.New("invalid or expired refresh token")
+	ErrSessionNotFound     = errors.New("session not found")
+	ErrInstitutionNotFound = errors.New("institution not found")
+)
+
+// Refresh exchanges a refresh token for a new access and refresh token pair.
+// Each refresh token works once; presenting a used one again means it leaked,
+// so the whole session is revoked.
+func (s *Service) Refresh(refreshToken, deviceID string) (*entity.Tokens, error) {
+	token, err := s.repo.GetRefreshTokenByHash(entity.HashToken(refreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidRefreshToken
+	} else if err != nil {
+		log.Printf("unable to get refresh token, err=%v", err)
+		return nil, err
+	}
+
+	session, err := s.repo.GetSession(token.SessionID)
+	if err != nil {
+		log.Printf("unable to get session %s, err=%v", token.SessionID, err)
+		return nil, err
+	}
+	if session.RevokedAt != nil || time.Now().After(token.ExpiresAt) {
+		return nil, ErrInvalidRefreshToken
+	}
+	if session.DeviceID != deviceID {
+		log.Printf("refresh token for session %s presented from another device", session.SessionID)
+		return nil, ErrInvalidRefreshToken
+	}
+
+	fresh, err := s.repo.MarkRefreshTokenUsed(token.TokenID)
+	if err != nil {
+		log.Printf("unable to mark refresh token as used, err=%v", err)
+		return nil, err
+	}
+	if !fresh {
+		log.Printf("refresh token reuse detected, revoking session %s", session.SessionID)
+		if err := s.repo.RevokeSession(session.SessionID); err != nil {
+			log.Printf("unable to revoke session %s, err=%v", session.SessionID, err)
+		} else {
+			s.recordEvent(auth.Event{
+				Type:          auth.EventTokenRevoked,
+				InstitutionID: session.InstitutionID,
+				SubjectID:     session.UserID,
+				Detail:        "session " + session.SessionID + ": refresh token reused",
+			})
+		}
+		return nil, ErrInvalidRefreshToken
+	}
+
+	user, err := s.repo.GetUserByID(session.UserID)
+	if err != nil {
+		log.Printf("unable to get user by id, err=%v", err)
+		return nil, err
+	}
+	if err := s.checkLoginAllowed(user); errors.Is(err, ErrUserDeactivated) {
+		return nil, ErrInvalidRefreshToken
+	} else if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	if err := s.repo.TouchSession(session.SessionID, now, now); err != nil {
+		log.Printf("unable to record use of session %s, err=%v", session.SessionID, err)
+	}
+	return s.issueTokens(user, session)
+}
+
+// Logout ends the caller's session and, if present, the session of the refresh token.
+func (s *Service) Logout(principal *auth.Principal, refreshToken string) error {
+	if err := requireSession(principal); err != nil {
+		return err
+	}
+
+	if err := s.revocations.Revoke(principal.TokenID, principal.ExpiresAt); err != nil {
+		log.Printf("unable to revoke token %s, err=%v", principal.TokenID, err)
+		return err
+	}
+	if err := s.repo.RevokeSession(principal.SessionID); err != nil {
+		log.Printf("unable to revoke session %s, err=%v", principal.SessionID, err)
+		return err
+	}
+	s.recordTokenRevoked(principal, principal.UserID, "session "+principal.SessionID+": logout")
+
+	if refreshToken == "" {
+		return nil
+	}
+	token, err := s.repo.GetRefreshTokenByHash(entity.HashToken(refreshToken))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		log.Printf("unable to get refresh token, err=%v", err)
+		return err
+	}
+	if token.SessionID == principal.SessionID {
+		return nil
+	}
+	if err := s.repo.RevokeSession(token.SessionID); err != nil {
+		log.Printf("unable to revoke session %s, err=%v", token.SessionID, err)
+		return err
+	}
+	s.recordTokenRevoked(principal, principal.UserID, "session "+token.SessionID+": logout")
+	return nil
+}
+
+// ListSessions returns the caller's sessions that can still be refreshed,
+// most recently seen first.
+func (s *Service) ListSessions(principal *auth.Principal) ([]*entity.Session, error) {
+	if err := requireSession(principal); err != nil {
+		return nil, err
+	}
+
+	sessions, err := s.repo.ListActiveSessions(principal.UserID, time.Now().Add(-refreshTokenTTL))
+	if err != nil {
+		log.Printf("unable to list sessions of user %s, err=%v", principal.UserID, err)
+		return nil, err
+	}
+	return sessions, nil
+}
+
+// RevokeSession signs the caller out of one of their sessions, which may be
+// the current one.
+func (s *Service) RevokeSession(principal *auth.Principal, sessionID string) error {
+	if err := requireSession(principal); err != nil {
+		return err
+	}
+
+	revoked, err := s.repo.RevokeUserSession(sessionID, principal.UserID)
+	if err != nil {
+		log.Printf("unable to revoke session %s, err=%v", sessionID, err)
+		return err
+	}
+	if !revoked {
+		return ErrSessionNotFound
+	}
+	s.recordTokenRevoked(principal, principal.UserID, "session "+sessionID+": signed out")
+	return nil
+}
+
+// RevokeUserSessions signs a user out of every session, for example after
+// their device was lost. Their API keys are not affected.
+func (s *Service) RevokeUserSessions(principal *auth.Principal, userID string) error {
+	if err := s.authz.Authorize(principal, common.UserSignOut); err != nil {
+		return err
+	}
+
+	if _, err := s.getManagedUser(principal, userID); err != nil {
+		return err
+	}
+	if err := s.repo.RevokeUserSessions(userID); err != nil {
+		log.Printf("unable to revoke sessions of user %s, err=%v", userID, err)
+		return err
+	}
+	s.recordTokenRevoked(principal, userID, "all sessions: signed out")
+	return nil
+}
+
+// SwitchInstitution moves a super-admin's session to another institution,
+// whose users and companies the returned tokens then work with. The current
+// access token is revoked since it still names the old institution.
+func (s *Service) SwitchInstitution(principal *auth.Principal, institutionID string) (*entity.Tokens, error) {
+	if err := requireSession(principal); err != nil {
+		return nil, err
+	}
+	if err := s.authz.Authorize(principal, common.InstitutionManage); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.repo.GetInstitution(institutionID); errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInstitutionNotFound
+	} else if err != nil {
+		log.Printf("unable to get institution %s, err=%v", institutionID, err)
+		return nil, err
+	}
+	session, err := s.repo.GetSession(principal.SessionID)
+	if err != nil {
+		log.Printf("unable to get session %s, err=%v", principal.SessionID, err)
+		return nil, err
+	}
+	if session.RevokedAt != nil {
+		return nil, auth.ErrUnauthenticated
+	}
+	if session.InstitutionID != institutionID {
+		switched, err := s.repo.SwitchSessionInstitution(session.SessionID, institutionID)
+		if err != nil {
+			log.Printf("unable to switch session %s to institution %s, err=%v", session.SessionID, institutionID, err)
+			return nil, err
+		}
+		if !switched {
+			return nil, auth.ErrUnauthenticated
+		}
+		session.InstitutionID = institutionID
+	}
+
+	if err := s.revocations.Revoke(principal.TokenID, principal.ExpiresAt); err != nil {
+		log.Printf("unable to revoke token %s, err=%v", principal.TokenID, err)
+		return nil, err
+	}
+	user, err := s.getUser(principal.UserID)
+	if err != nil {
+		return nil, err
+	}
+	return s.issueTokens(user, session)
+}
+
+// recordTokenRevoked logs sessions or keys of subjectID that the caller revoked.
+func (s *Service) recordTokenRevoked(principal *auth.Principal, subjectID, detail string) {
+	s.recordEvent(auth.Event{
+		Type:          auth.EventTokenRevoked,
+		InstitutionID: principal.InstitutionID,
+		ActorID:       principal.UserID,
+		SubjectID:     subjectID,
+		IP:            principal.IP,
+		Detail:        detail,
+	})
+}
+
+func (s *Service) issueTokens(user *entity.User, session *entity.Session) (*entity.Tokens, error) {
+	expiresAt := time.Now().Add(accessTokenTTL)
+	accessToken, err := s.generateJWT(user, session, expiresAt)
+	if err != nil {
+		log.Printf("unable to generate JWT, err=%v", err)
+		return nil, err
+	}
+
+	refresh, refreshToken, err := entity.NewRefreshToken(session.SessionID, refreshTokenTTL)
+	if err != nil {
+		log.Printf("unable to generate refresh token, err=%v", err)
+		return nil, err
+	}
+	if err := s.repo.CreateRefreshToken(refresh); err != nil {
+		log.Printf("unable to store refresh token, err=%v", err)
+		return nil, err
+	}
+
+	return &entity.Tokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		DeviceID:     session.DeviceID,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
@@ -0,0 +1,176 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"backend/services/userd/entity"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+)
+
+const oidcLoginTTL = 10 * time.Minute
+
+var (
+	ErrSSODisabled        = errors.New("single sign-on is not configured")
+	ErrInvalidOIDCState   = errors.New("invalid, expired or already used single sign-on state")
+	ErrSSOEmailUnverified = errors.New("identity provider did not verify the email address")
+	ErrSSONoRole          = errors.New("none of the identity provider groups map to a role")
+)
+
+// OIDCProvider is the identity provider used for single sign-on.
+type OIDCProvider interface {
+	AuthCodeURL(state, nonce, codeVerifier string) string
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*auth.Identity, error)
+}
+
+// SSO configures single sign-on through an OpenID Connect provider.
+type SSO struct {
+	Provider OIDCProvider
+	Roles    auth.RoleMapping
+	// DefaultRole is given to new users none of whose groups are mapped.
+	// When empty such users are refused.
+	DefaultRole string
+}
+
+// StartOIDCLogin returns the identity provider URL that starts a single sign-on login.
+func (s *Service) StartOIDCLogin(deviceID string) (string, error) {
+	if s.sso == nil {
+		return "", ErrSSODisabled
+	}
+
+	login, state, err := entity.NewOIDCLogin(deviceID, oidcLoginTTL)
+	if err != nil {
+		log.Printf("unable to create OIDC login entity, err=%v", err)
+		return "", err
+	}
+	if err := s.repo.CreateOIDCLogin(login); err != nil {
+		log.Printf("unable to create OIDC login in repository, err=%v", err)
+		return "", err
+	}
+	return s.sso.Provider.AuthCodeURL(state, login.Nonce, login.CodeVerifier), nil
+}
+
+// FinishOIDCLogin handles the identity provider callback. The user is matched
+// by email, or provisioned on first login, and takes the role mapped from
+// their groups when one matches.
+func (s *Service) FinishOIDCLogin(ctx context.Context, state, code string, client entity.Client) (*entity.LoginResult, error) {
+	if s.sso == nil {
+		return nil, ErrSSODisabled
+	}
+
+	login, err := s.repo.GetOIDCLogin(entity.HashToken(state))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidOIDCState
+	} else if err != nil {
+		log.Printf("unable to get OIDC login, err=%v", err)
+		return nil, err
+	}
+	if login.UsedAt != nil || time.Now().After(login.ExpiresAt) {
+		return nil, ErrInvalidOIDCState
+	}
+	claimed, err := s.repo.ClaimOIDCLogin(login.StateHash)
+	if err != nil {
+		log.Printf("unable to claim OIDC login, err=%v", err)
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidOIDCState
+	}
+
+	identity, err := s.sso.Provider.Exchange(ctx, code, login.CodeVerifier, login.Nonce)
+	if err != nil {
+		log.Printf("unable to complete OIDC login, err=%v", err)
+		s.recordEvent(auth.Event{
+			Type:   auth.EventLoginFailed,
+			IP:     client.IP,
+			Detail: "single sign-on rejected",
+		})
+		return nil, ErrInvalidCredentials
+	}
+	// Matching by email is only safe for addresses the provider checked
+	if identity.Email == "" || !identity.EmailVerified {
+		s.recordEvent(auth.Event{
+			Type:   auth.EventLoginFailed,
+			Email:  identity.Email,
+			IP:     client.IP,
+			Detail: ErrSSOEmailUnverified.Error(),
+		})
+		return nil, ErrSSOEmailUnverified
+	}
+
+	role, mapped := s.sso.Roles.Role(identity.Groups)
+	user, err := s.syncExternalUser(identity.Email, identity.Name, role, mapped, s.sso.DefaultRole)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.checkLoginAllowed(user); err != nil {
+		s.recordLoginRefused(user, client, err)
+		return nil, err
+	}
+	return s.completeLogin(user, login.DeviceID, client)
+}
+
+// syncExternalUser returns the user an external directory authenticated,
+// creating it on first login. Mapped roles replace the stored role, since
+// the directory is the source of truth for its users' groups.
+func (s *Service) syncExternalUser(email, name, role string, mapped bool, defaultRole string) (*entity.User, error) {
+	user, err := s.repo.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		if !mapped {
+			if defaultRole == "" {
+				return nil, ErrSSONoRole
+			}
+			role = defaultRole
+		}
+		if name == "" {
+			name, _, _ = strings.Cut(email, "@")
+		}
+		// External users sign in through their directory, so the local
+		// password is a random one nobody knows until they reset it
+		pass, err := entity.NewRandomPassword()
+		if err != nil {
+			return nil, err
+		}
+		// Directories are configured per deployment, not per institution
+		return s.createUser(common.DefaultInstitutionID, name, email, pass, role, true)
+	} else if err != nil {
+		log.Printf("unable to get user by email, err=%v", err)
+		return nil, err
+	}
+
+	if !user.IsEmailVerified() {
+		user.MarkEmailVerified()
+		if err := s.repo.MarkEmailVerified(user.UserID, *user.EmailVerifiedAt); err != nil {
+			log.Printf("unable to mark email of user %s as verified, err=%v", user.UserID, err)
+			return nil, err
+		}
+	}
+
+	if mapped && role != user.Role {
+		if err := s.checkRoleExists(user.InstitutionID, role); err != nil {
+			return nil, err
+		}
+		if err := s.repo.UpdateUserRole(user.InstitutionID, user.UserID, role); err != nil {
+			log.Printf("unable to update role of user %s, err=%v", user.UserID, err)
+			return nil, err
+		}
+		// Tokens issued before carry the old role
+		if err := s.repo.RevokeUserSessions(user.UserID); err != nil {
+			log.Printf("unable to revoke sessions of user %s, err=%v", user.UserID, err)
+			return nil, err
+		}
+		s.recordEvent(auth.Event{
+			Type:          auth.EventRoleChanged,
+			InstitutionID: user.InstitutionID,
+			SubjectID:     user.UserID,
+			Detail:        fmt.Sprintf("'%s' to '%s' from directory groups", user.Role, role),
+		})
+		user.Role = role
+	}
+	return user, nil
+}
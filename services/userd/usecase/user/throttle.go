@@ -0,0 +1,122 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/common"
+	"log"
+	"strings"
+)
+
+// Login attempts are throttled per email address, which locks the account
+// after repeated failures, and per client IP, which only slows it down.
+func emailThrottleKey(email string) string {
+	// Emails are compared case-insensitively by the database
+	return "email:" + strings.ToLower(email)
+}
+
+func ipThrottleKey(ip string) string {
+	return "ip:" + ip
+}
+
+// loginAttempt is a login counted against the throttle before its password
+// is checked, so concurrent guesses cannot all slip past the limits.
+type loginAttempt struct {
+	email string
+	ip    string
+	// locked is set when counting the attempt locked the account
+	locked bool
+}
+
+// beginLoginAttempt reserves an attempt for the email and the IP. It must
+// be finished with recordLoginFailure, recordLoginSuccess or
+// abandonLoginAttempt.
+func (s *Service) beginLoginAttempt(email, ip string) (*loginAttempt, error) {
+	attempt := &loginAttempt{email: email, ip: ip}
+	locked, err := s.throttle.Attempt(emailThrottleKey(email), true)
+	if err != nil {
+		return nil, err
+	}
+	attempt.locked = locked
+	if ip != "" {
+		if _, err := s.throttle.Attempt(ipThrottleKey(ip), false); err != nil {
+			if err := s.throttle.Release(emailThrottleKey(email), locked); err != nil {
+				log.Printf("unable to release login attempt, err=%v", err)
+			}
+			return nil, err
+		}
+	}
+	return attempt, nil
+}
+
+// recordLoginFailure logs a rejected password, which the attempt already
+// counted. Emails are kept out of the logs; the audit log, which only admins
+// can read, has them. Failures for unknown emails belong to no institution.
+func (s *Service) recordLoginFailure(attempt *loginAttempt) {
+	event := auth.Event{
+		Type:   auth.EventLoginFailed,
+		Email:  attempt.email,
+		IP:     attempt.ip,
+		Detail: ErrInvalidCredentials.Error(),
+	}
+	if user, err := s.repo.GetUserByEmail(attempt.email); err == nil {
+		event.InstitutionID = user.InstitutionID
+		event.SubjectID = user.UserID
+	}
+	s.recordEvent(event)
+
+	if attempt.locked {
+		event.Type = auth.EventAccountLocked
+		event.Detail = "too many failed logins"
+		s.recordEvent(event)
+	}
+}
+
+// recordLoginSuccess clears the failures of the account. Earlier failures of
+// the IP are kept, so one valid account cannot be used to keep guessing
+// others.
+func (s *Service) recordLoginSuccess(attempt *loginAttempt) {
+	if err := s.throttle.Reset(emailThrottleKey(attempt.email)); err != nil {
+		log.Printf("unable to reset failed logins, err=%v", err)
+	}
+	s.releaseIPAttempt(attempt)
+}
+
+// abandonLoginAttempt takes back an attempt whose password could not be
+// checked.
+func (s *Service) abandonLoginAttempt(attempt *loginAttempt) {
+	if err := s.throttle.Release(emailThrottleKey(attempt.email), attempt.locked); err != nil {
+		log.Printf("unable to release login attempt, err=%v", err)
+	}
+	s.releaseIPAttempt(attempt)
+}
+
+func (s *Service) releaseIPAttempt(attempt *loginAttempt) {
+	if attempt.ip == "" {
+		return
+	}
+	if err := s.throttle.Release(ipThrottleKey(attempt.ip), false); err != nil {
+		log.Printf("unable to release login attempt from %s, err=%v", attempt.ip, err)
+	}
+}
+
+// UnlockUser lifts a lockout caused by failed logins or failed two-factor
+// codes before it expires.
+func (s *Service) UnlockUser(principal *auth.Principal, userID string) error {
+	if err := s.authz.Authorize(principal, common.UserUnlock); err != nil {
+		return err
+	}
+
+	user, err := s.getInstitutionUser(principal.InstitutionID, userID)
+	if err != nil {
+		return err
+	}
+	if err := s.throttle.Reset(emailThrottleKey(user.Email)); err != nil {
+		log.Printf("unable to unlock user %s, err=%v", userID, err)
+		return err
+	}
+	if err := s.throttle.Reset(mfaThrottleKey(user.UserID)); err != nil {
+		log.Printf("unable to unlock two-factor authentication of user %s, err=%v", userID, err)
+		return err
+	}
+	return nil
+}
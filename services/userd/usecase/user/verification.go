@@ -0,0 +1,110 @@
+package user
+
+import (
+	"backend/pkg/auth"
+	"backend/pkg/mailer"
+	"backend/services/userd/entity"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+const emailVerificationTTL = time.Hour * 24
+
+var ErrInvalidVerificationToken = errors.New("invalid, expired or already used verification token")
+
+// sendVerification emails a verification token for the user's current address.
+// Failures are only logged, so an unreachable mail server does not block sign-up.
+func (s *Service) sendVerification(user *entity.User) {
+	verification, token, err := entity.NewEmailVerification(user.UserID, user.Email, emailVerificationTTL)
+	if err != nil {
+		log.Printf("unable to create email verification entity, err=%v", err)
+		return
+	}
+	if err := s.repo.CreateEmailVerification(verification); err != nil {
+		log.Printf("unable to create email verification in repository, err=%v", err)
+		return
+	}
+
+	if err := s.mailer.Send(mailer.Message{
+		To:      user.Email,
+		Subject: "Verify your email address",
+		Body: fmt.Sprintf("Hi %s,\n\n"+
+			"Use this token to verify your email address. It expires in %s:\n\n"+
+			"%s",
+			user.UserName, emailVerificationTTL, token),
+	}); err != nil {
+		log.Printf("unable to send verification email to user %s, err=%v", user.UserID, err)
+	}
+}
+
+// VerifyEmail marks the address a verification token was sent to as verified.
+// Tokens sent to an address the user has since changed away from are rejected.
+func (s *Service) VerifyEmail(token string) (*entity.User, error) {
+	verification, err := s.repo.GetEmailVerificationByHash(entity.HashToken(token))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrInvalidVerificationToken
+	} else if err != nil {
+		log.Printf("unable to get email verification, err=%v", err)
+		return nil, err
+	}
+	if verification.UsedAt != nil || time.Now().After(verification.ExpiresAt) {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user, err := s.getUser(verification.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if user.Email != verification.Email {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	claimed, err := s.repo.ClaimEmailVerification(verification.TokenID)
+	if err != nil {
+		log.Printf("unable to claim email verification %s, err=%v", verification.TokenID, err)
+		return nil, err
+	}
+	if !claimed {
+		return nil, ErrInvalidVerificationToken
+	}
+
+	user.MarkEmailVerified()
+	if err := s.repo.MarkEmailVerified(user.UserID, *user.EmailVerifiedAt); err != nil {
+		log.Printf("unable to mark email of user %s as verified, err=%v", user.UserID, err)
+		return nil, err
+	}
+	return user, nil
+}
+
+// ResendVerification emails a new verification token. Like ForgotPassword it
+// reports success for unknown addresses.
+func (s *Service) ResendVerification(email string) error {
+	user, err := s.repo.GetUserByEmail(email)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil
+	} else if err != nil {
+		log.Printf("unable to get user by email, err=%v", err)
+		return err
+	}
+	if !user.IsActive() || user.IsEmailVerified() {
+		return nil
+	}
+
+	s.sendVerification(user)
+	return nil
+}
+
+// checkLoginAllowed rejects deactivated users, and unverified ones when the
+// email policy requires verification before login.
+func (s *Service) checkLoginAllowed(user *entity.User) error {
+	if !user.IsActive() {
+		return ErrUserDeactivated
+	}
+	if s.authz.EmailPolicy() == auth.EmailPolicyLogin && !user.IsEmailVerified() {
+		return auth.ErrEmailUnverified
+	}
+	return nil
+}
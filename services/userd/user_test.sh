@@ -2,6 +2,9 @@
 
 # Define the base URL
 BASE_URL="http://localhost:8080/v1/user"
+# Bootstrap admin, created with: make create-admin EMAIL=admin@gmail.com PASS=Placement#2024
+ADMIN_EMAIL="${ADMIN_EMAIL:-admin@gmail.com}"
+ADMIN_PASS="${ADMIN_PASS:-Placement#2024}"
 
 # Initialize counters
 PASSED=0
@@ -22,6 +25,7 @@ test_post() {
     # Make the POST request and capture the HTTP status code and response body
     response=$(curl -s -w "\n%{http_code}" -X POST \
         -H "Content-Type: application/json" \
+        -H "Authorization: Bearer $ADMIN_JWT" \
         -d "$json_data" \
         "$BASE_URL")
     
@@ -70,7 +74,9 @@ test_get() {
     echo "Endpoint: $endpoint"
     
     # Make the GET request and capture the HTTP status code and response body
-    response=$(curl -s -w "\n%{http_code}" -X GET "$endpoint")
+    response=$(curl -s -w "\n%{http_code}" -X GET \
+        -H "Authorization: Bearer $ADMIN_JWT" \
+        "$endpoint")
     
     # Extract status code (last line)
     status_code=$(echo "$response" | tail -n1)
@@ -91,59 +97,89 @@ test_get() {
     echo "------------------------"
 }
 
+# Login as the bootstrap admin, creating and reading users requires a token
+ADMIN_JWT=$(curl -s -X POST \
+    -H "Content-Type: application/json" \
+    -d "{\"email\": \"$ADMIN_EMAIL\", \"pass\": \"$ADMIN_PASS\"}" \
+    "http://localhost:8080/v1/login" | jq -r '.jwt_token // empty')
+if [ -z "$ADMIN_JWT" ]; then
+    echo "Unable to login as $ADMIN_EMAIL, create it with make create-admin"
+    exit 1
+fi
+
 # Test health endpoint
 echo "Testing health endpoint"
 test_get "Health endpoint" "$BASE_URL/health" 200
 
 # Test Case 1: Admin user creation - Capture the ID
 test_post "Admin user creation" \
-    '{"user_name": "admin", "email": "admin@gmail.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "admin1", "email": "admin1@gmail.com", "pass": "Placement#2024", "role": "admin"}' \
     200 \
     "capture" # Add flag to capture ID
 
+# Test Case 1b: User creation without a token
+ADMIN_JWT="" test_post "User creation without token" \
+    '{"user_name": "anon", "email": "anon@gmail.com", "pass": "Placement#2024", "role": "admin"}' \
+    401
+
 # Test Case 2: manager user creation
 test_post "manager user creation" \
-    '{"user_name": "soorya", "email": "inst@gmail.com", "pass": "test1@123", "role": "manager"}' \
+    '{"user_name": "soorya", "email": "inst@gmail.com", "pass": "Placement#2024", "role": "manager"}' \
     200
 
 # Test Case 3: Valid user creation
 test_post "Valid user creation" \
-    '{"user_name": "testuser", "email": "testuser@gmail.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "testuser", "email": "testuser@gmail.com", "pass": "Placement#2024", "role": "admin"}' \
     200
 
 # Test Case 4: Duplicate user
 test_post "Duplicate user" \
-    '{"user_name": "testuser", "email": "testuser@gmail.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "testuser", "email": "testuser@gmail.com", "pass": "Placement#2024", "role": "admin"}' \
     500
 
 # Test Case 5: New email, same username
 test_post "New email, same username" \
-    '{"user_name": "testuser", "email": "testuser2@gmail.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "testuser", "email": "testuser2@gmail.com", "pass": "Placement#2024", "role": "admin"}' \
     200
 
 # Test Case 6: Duplicate email, different role
 test_post "Duplicate email, different role" \
-    '{"user_name": "testuser3", "email": "testuser2@gmail.com", "pass": "test1@123", "role": "tester"}' \
+    '{"user_name": "testuser3", "email": "testuser2@gmail.com", "pass": "Placement#2024", "role": "manager"}' \
     500
 
+# Test Case 6b: Unknown role
+test_post "Unknown role" \
+    '{"user_name": "testuser3", "email": "testuser3@gmail.com", "pass": "Placement#2024", "role": "tester"}' \
+    400
+
 # Test Case 7: Invalid email format (missing domain)
 test_post "Invalid email format 1" \
-    '{"user_name": "testuser4", "email": "testuser4@.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "testuser4", "email": "testuser4@.com", "pass": "Placement#2024", "role": "admin"}' \
     500
 
 # Test Case 8: Invalid email format (incomplete)
 test_post "Invalid email format 2" \
-    '{"user_name": "testuser5", "email": "@s.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "testuser5", "email": "@s.com", "pass": "Placement#2024", "role": "admin"}' \
     500
 
 # Test Case 9: Empty password
 test_post "Empty password" \
     '{"user_name": "testuser6", "email": "testuser6@gmail.com", "pass": "", "role": "admin"}' \
-    500
+    400
+
+# Test Case 9b: Password from the breached password blocklist
+test_post "Breached password" \
+    '{"user_name": "testuser6", "email": "testuser6@gmail.com", "pass": "test1@123", "role": "admin"}' \
+    400
+
+# Test Case 9c: Password too short and missing character classes
+test_post "Weak password" \
+    '{"user_name": "testuser6", "email": "testuser6@gmail.com", "pass": "short", "role": "admin"}' \
+    400
 
 # Test Case 10: Empty username
 test_post "Empty username" \
-    '{"user_name": "", "email": "testuser7@gmail.com", "pass": "test1@123", "role": "admin"}' \
+    '{"user_name": "", "email": "testuser7@gmail.com", "pass": "Placement#2024", "role": "admin"}' \
     500
 
 # Test Case 11: Get user by ID (using captured ID)